@@ -4,23 +4,34 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"github.com/prodanlabs/karmada-examples/cmd/custom-controller-manager/app/options"
-	"github.com/prodanlabs/karmada-examples/pkg/controllers/deployment"
-	"github.com/prodanlabs/karmada-examples/pkg/controllers/dns"
-	"github.com/prodanlabs/karmada-examples/pkg/util"
+	"net"
+	"strconv"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
-	"net"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"strconv"
-	"time"
+
+	"github.com/prodanlabs/karmada-examples/cmd/custom-controller-manager/app/options"
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
 const (
 	CheckEndpointHealthz = "healthz"
 	CheckEndpointReadyz  = "readyz"
+	CheckKarmadaAPIs     = "karmada-apis"
+	CheckInformerSync    = "informer-sync"
 )
 
 func NewCustomControllerManagerCommand(ctx context.Context) *cobra.Command {
@@ -30,7 +41,10 @@ func NewCustomControllerManagerCommand(ctx context.Context) *cobra.Command {
 		Use:  "karmada-custom-controller-manager",
 		Long: `karmada custom controller manager`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := opts.Validate(); err != nil {
+			if err := opts.Complete(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := opts.Validate(controllers.ControllerNames()); err != nil {
 				return err
 			}
 			return Run(ctx, opts)
@@ -39,7 +53,7 @@ func NewCustomControllerManagerCommand(ctx context.Context) *cobra.Command {
 
 	klog.InitFlags(flag.CommandLine)
 	cmd.Flags().AddGoFlagSet(flag.CommandLine)
-	opts.AddFlags(cmd.Flags())
+	opts.AddFlags(cmd.Flags(), controllers.ControllerNames())
 	return cmd
 }
 
@@ -48,7 +62,13 @@ func Run(ctx context.Context, opts *options.Options) error {
 	if err != nil {
 		return err
 	}
-	util.SetupKubeConfig(config)
+	util.SetupKubeConfig(config, opts.ClientConnection)
+
+	tp, err := tracing.Setup(ctx, "karmada-custom-controller-manager", opts.Tracing.Configuration(), config)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %v", err)
+	}
+	defer tracing.Shutdown(tp)
 
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme:                     runtime.NewScheme(),
@@ -57,8 +77,13 @@ func Run(ctx context.Context, opts *options.Options) error {
 		LeaderElectionID:           opts.LeaderElection.ResourceName,
 		LeaderElectionNamespace:    opts.LeaderElection.ResourceNamespace,
 		LeaderElectionResourceLock: opts.LeaderElection.ResourceLock,
+		LeaseDuration:              &opts.LeaderElection.LeaseDuration.Duration,
+		RenewDeadline:              &opts.LeaderElection.RenewDeadline.Duration,
+		RetryPeriod:                &opts.LeaderElection.RetryPeriod.Duration,
 		HealthProbeBindAddress:     net.JoinHostPort(opts.BindAddress, strconv.Itoa(opts.SecurePort)),
 		MetricsBindAddress:         opts.MetricsBindAddress,
+		// the deployment controller reads the configured kinds as unstructured objects, serve them from the informers.
+		NewClient: cluster.ClientBuilderWithOptions(cluster.ClientOptions{CacheUnstructured: true}),
 	})
 	if err != nil {
 		return fmt.Errorf("new controller manager failed: %v", err)
@@ -70,16 +95,32 @@ func Run(ctx context.Context, opts *options.Options) error {
 	if err := mgr.AddReadyzCheck(CheckEndpointReadyz, healthz.Ping); err != nil {
 		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckEndpointReadyz, err)
 	}
-
-	if err := deployment.AddToManager(mgr); err != nil {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
 		return err
 	}
+	if err := mgr.AddReadyzCheck(CheckKarmadaAPIs, util.APIResourcesChecker(discoveryClient, map[schema.GroupVersion][]string{
+		clusterv1alpha1.SchemeGroupVersion:       {"clusters"},
+		workv1alpha1.SchemeGroupVersion:          {"works"},
+		policyv1alpha1.SchemeGroupVersion:        {"propagationpolicies"},
+		bootstrappingv1alpha1.SchemeGroupVersion: {"bootstrappolicies"},
+	})); err != nil {
+		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckKarmadaAPIs, err)
+	}
+	if err := mgr.AddReadyzCheck(CheckInformerSync, util.CacheSyncChecker(mgr.GetCache())); err != nil {
+		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckInformerSync, err)
+	}
 
-	dnsController := dns.NewController(mgr)
-	if err := dnsController.AddToManager(mgr); err != nil {
+	metrics.RecordFeatureGates(features.FeatureGate)
+
+	controllerContext := ControllerContext{
+		Ctx:  ctx,
+		Mgr:  mgr,
+		Opts: opts,
+	}
+	if err := controllers.StartControllers(controllerContext); err != nil {
 		return err
 	}
-	go dnsController.Worker(5 * time.Second)
 
 	if err := mgr.Start(ctx); err != nil {
 		return fmt.Errorf("controller manager exit: %v", err)
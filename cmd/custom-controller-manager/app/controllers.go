@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/prodanlabs/karmada-examples/cmd/custom-controller-manager/app/options"
+	"github.com/prodanlabs/karmada-examples/pkg/controllers/deployment"
+	"github.com/prodanlabs/karmada-examples/pkg/controllers/dns"
+)
+
+// ControllerContext defines the context object for controller.
+type ControllerContext struct {
+	Ctx  context.Context
+	Mgr  manager.Manager
+	Opts *options.Options
+}
+
+// InitFunc is used to launch a particular controller.
+// Any error returned will cause the controller process to `Fatal`.
+// The bool indicates whether the controller was enabled.
+type InitFunc func(ctx ControllerContext) (enabled bool, err error)
+
+// Initializers is a public map of named controller groups.
+type Initializers map[string]InitFunc
+
+// ControllerNames returns all known controller names.
+func (i Initializers) ControllerNames() []string {
+	names := make([]string, 0, len(i))
+	for name := range i {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartControllers starts a set of controllers with a specified ControllerContext.
+func (i Initializers) StartControllers(ctx ControllerContext) error {
+	for _, name := range i.ControllerNames() {
+		if !ctx.Opts.IsControllerEnabled(name) {
+			klog.Warningf("%q is disabled", name)
+			continue
+		}
+		klog.V(1).Infof("Starting %q", name)
+		started, err := i[name](ctx)
+		if err != nil {
+			klog.Errorf("Error starting %q", name)
+			return fmt.Errorf("error starting %q: %v", name, err)
+		}
+		if !started {
+			klog.Warningf("Skipping %q", name)
+			continue
+		}
+		klog.Infof("Started %q", name)
+	}
+	return nil
+}
+
+var controllers = make(Initializers)
+
+func init() {
+	controllers["deployment"] = startDeploymentController
+	controllers["dns"] = startDNSController
+}
+
+func startDeploymentController(ctx ControllerContext) (bool, error) {
+	if err := deployment.AddToManager(ctx.Mgr, ctx.Opts.Deployment, ctx.Opts.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func startDNSController(ctx ControllerContext) (bool, error) {
+	dnsController := dns.NewController(ctx.Mgr, ctx.Opts.DNS, ctx.Opts.ClientConnection, ctx.Opts.DryRun)
+	if err := dnsController.AddToManager(ctx.Mgr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
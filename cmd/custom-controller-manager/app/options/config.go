@@ -0,0 +1,39 @@
+package options
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+)
+
+var (
+	configScheme = runtime.NewScheme()
+	configCodecs = serializer.NewCodecFactory(configScheme, serializer.EnableStrict)
+)
+
+func init() {
+	var _ = configv1alpha1.AddToScheme(configScheme)
+}
+
+// LoadConfigFile reads and defaults the custom-controller-manager configuration file.
+func LoadConfigFile(file string) (*configv1alpha1.CustomControllerManagerConfiguration, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %v", file, err)
+	}
+
+	obj, gvk, err := configCodecs.UniversalDecoder(configv1alpha1.SchemeGroupVersion).Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config file %q: %v", file, err)
+	}
+	cfg, ok := obj.(*configv1alpha1.CustomControllerManagerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("unexpected config type %v in %q", gvk, file)
+	}
+	return cfg, nil
+}
@@ -1,50 +1,196 @@
 package options
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/pflag"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	componentbaseconfig "k8s.io/component-base/config"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
 type Options struct {
-	BindAddress        string
-	SecurePort         int
-	LeaderElection     componentbaseconfig.LeaderElectionConfiguration
-	DisableControllers string
-	EnableControllers  string
+	// ConfigFile is the location of the custom-controller-manager's configuration file.
+	// Values set by flags override the ones loaded from the file.
+	ConfigFile     string
+	BindAddress    string
+	SecurePort     int
+	LeaderElection componentbaseconfig.LeaderElectionConfiguration
+	// ClientConnection specifies the QPS, burst and content type of the clients talking to the karmada apiserver.
+	ClientConnection componentbaseconfig.ClientConnectionConfiguration
+	// EnableControllers is the list of controllers to enable or disable.
+	// '*' means "all enabled by default controllers"
+	// 'foo' means "enable 'foo'"
+	// '-foo' means "disable 'foo'"
+	// first item for a particular name wins
+	EnableControllers []string
+	// DisableControllers is the list of controllers to disable, it takes
+	// precedence over EnableControllers.
+	DisableControllers []string
 	MetricsBindAddress string
 	ResyncPeriod       metav1.Duration
+	// Deployment holds the configuration of the deployment controller.
+	Deployment configv1alpha1.DeploymentControllerConfiguration
+	// DNS holds the configuration of the dns controller.
+	DNS configv1alpha1.DNSControllerConfiguration
+	// DryRun makes the controllers compute and log what they would change without writing anything.
+	DryRun bool
+	// Tracing holds the configuration of the OTLP trace exporter.
+	Tracing tracing.Options
 }
 
-// NewOptions builds an empty options.
+// NewOptions builds an options with the default configuration.
 func NewOptions() *Options {
-	return &Options{
-		LeaderElection: componentbaseconfig.LeaderElectionConfiguration{
-			LeaderElect:  true,
-			ResourceLock: resourcelock.LeasesResourceLock,
-			ResourceName: "karmada-custom-controllers",
-		},
+	o := &Options{
+		Tracing: tracing.Options{SamplingRatePerMillion: tracing.DefaultSamplingRatePerMillion},
 	}
+	if err := o.ApplyConfiguration(configv1alpha1.NewDefaultConfiguration()); err != nil {
+		panic(err)
+	}
+	return o
 }
 
 // AddFlags adds flags to the specified FlagSet.
-func (o *Options) AddFlags(flags *pflag.FlagSet) {
+func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string) {
 	flags.Lookup("kubeconfig").Usage = "absolute path to the kubeconfig file"
+	flags.StringVar(&o.ConfigFile, "config", "", "The path to the configuration file. Flags override values in this file.")
 	flags.StringVar(&o.BindAddress, "bind-address", "0.0.0.0", "The IP address on which to listen for the --secure-port port.")
 	flags.IntVar(&o.SecurePort, "secure-port", 10258, "The secure port on which to serve HTTPS.")
-	flags.BoolVar(&o.LeaderElection.LeaderElect, "leader-elect", true, "Enable leader election for controller manager.")
-	flags.StringVarP(&o.LeaderElection.ResourceNamespace, "namespace", "n", "karmada-system", "Kubernetes namespace")
+	flags.BoolVar(&o.LeaderElection.LeaderElect, "leader-elect", o.LeaderElection.LeaderElect, "Enable leader election for controller manager.")
+	flags.StringVarP(&o.LeaderElection.ResourceNamespace, "namespace", "n", o.LeaderElection.ResourceNamespace, "Kubernetes namespace")
 	flags.StringVar(&o.MetricsBindAddress, "metrics-bind-address", ":8080", "The address the metrics bind to.")
-	flags.StringVar(&o.EnableControllers, "enable-controllers", "", "enabled controllers.")
-	flags.StringVar(&o.DisableControllers, "disable-controllers", "", "disable controllers.")
+	flags.StringSliceVar(&o.EnableControllers, "enable-controllers", o.EnableControllers, fmt.Sprintf(
+		"A list of controllers to enable. '*' enables all on-by-default controllers, 'foo' enables the controller named 'foo', '-foo' disables the controller named 'foo'. All controllers: %s.",
+		strings.Join(allControllers, ", ")))
+	flags.StringSliceVar(&o.DisableControllers, "disable-controllers", nil, "A list of controllers to disable, same as '-foo' in --enable-controllers.")
 	flags.DurationVar(&o.ResyncPeriod.Duration, "resync-period", 0, "informers resync period.")
+	flags.DurationVar(&o.Deployment.EvictionGracePeriod.Duration, "eviction-grace-period", o.Deployment.EvictionGracePeriod.Duration, "How long a member cluster may stay NotReady, or tainted NoExecute, before the Works placed on it are evicted, 0 never evicts them. Requires the ClusterHealthAwarePlacement feature.")
+	flags.DurationVar(&o.DNS.SyncInterval.Duration, "dns-sync-interval", o.DNS.SyncInterval.Duration, "The interval at which the dns controller syncs the Corefile.")
+	flags.DurationVar(&o.DNS.ClusterRequestTimeout.Duration, "cluster-request-timeout", o.DNS.ClusterRequestTimeout.Duration, "The timeout of a single request sent to a member cluster through the karmada cluster proxy.")
+	util.AddClientConnectionFlags(flags, &o.ClientConnection)
+	flags.BoolVar(&o.DryRun, "dry-run", false, "If true, the controllers only log the Works, PropagationPolicies and Corefile changes they would make, nothing is written.")
+	o.Tracing.AddFlags(flags)
+	features.FeatureGate.AddFlag(flags)
 }
 
-func (o *Options) Validate() error {
+// Complete loads the configuration file if one is given, the flags explicitly
+// set on the command line take precedence over the values in the file.
+func (o *Options) Complete(flags *pflag.FlagSet) error {
+	if o.ConfigFile == "" {
+		return nil
+	}
+
+	cfg, err := LoadConfigFile(o.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	// remember the flags set on the command line before they are overwritten by the file.
+	changed := map[*pflag.Flag][]string{}
+	flags.Visit(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			changed[f] = sv.GetSlice()
+			return
+		}
+		changed[f] = []string{f.Value.String()}
+	})
+
+	if err := o.ApplyConfiguration(cfg); err != nil {
+		return err
+	}
+
+	for f, value := range changed {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			if err := sv.Replace(value); err != nil {
+				return fmt.Errorf("failed to restore flag %q: %v", f.Name, err)
+			}
+			continue
+		}
+		if err := f.Value.Set(value[0]); err != nil {
+			return fmt.Errorf("failed to restore flag %q: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyConfiguration copies the values of the component configuration into Options.
+func (o *Options) ApplyConfiguration(cfg *configv1alpha1.CustomControllerManagerConfiguration) error {
+	if err := componentbaseconfigv1alpha1.Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(&cfg.LeaderElection, &o.LeaderElection, nil); err != nil {
+		return err
+	}
+	if err := componentbaseconfigv1alpha1.Convert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(&cfg.ClientConnection, &o.ClientConnection, nil); err != nil {
+		return err
+	}
+	o.EnableControllers = append([]string(nil), cfg.Controllers...)
+	o.Deployment = cfg.Deployment
+	o.DNS = cfg.DNS
+	o.Tracing.ApplyConfiguration(cfg.Tracing)
 	return nil
 }
 
-/*func (o *Options) Complete(args []string) error {
-        return nil
-}*/
+// Validate checks Options and return a slice of found errs.
+func (o *Options) Validate(allControllers []string) error {
+	known := sets.NewString(allControllers...)
+	for _, name := range o.EnableControllers {
+		if name == "*" {
+			continue
+		}
+		if !known.Has(strings.TrimPrefix(name, "-")) {
+			return fmt.Errorf("%q is not in the list of known controllers: %s", name, strings.Join(allControllers, ", "))
+		}
+	}
+	for _, name := range o.DisableControllers {
+		if !known.Has(name) {
+			return fmt.Errorf("%q is not in the list of known controllers: %s", name, strings.Join(allControllers, ", "))
+		}
+	}
+
+	cfg := &configv1alpha1.CustomControllerManagerConfiguration{
+		Deployment: o.Deployment,
+		DNS:        o.DNS,
+		Tracing:    o.Tracing.Configuration(),
+	}
+	if err := componentbaseconfigv1alpha1.Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(&o.LeaderElection, &cfg.LeaderElection, nil); err != nil {
+		return err
+	}
+	if err := componentbaseconfigv1alpha1.Convert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(&o.ClientConnection, &cfg.ClientConnection, nil); err != nil {
+		return err
+	}
+
+	errs := configv1alpha1.ValidateCustomControllerManagerConfiguration(cfg)
+	if o.ResyncPeriod.Duration < 0 {
+		errs = append(errs, field.Invalid(field.NewPath("resyncPeriod"), o.ResyncPeriod, "must be non-negative"))
+	}
+	return errs.ToAggregate()
+}
+
+// IsControllerEnabled check if a specified controller enabled or not.
+func (o *Options) IsControllerEnabled(name string) bool {
+	for _, ctrl := range o.DisableControllers {
+		if ctrl == name {
+			return false
+		}
+	}
+
+	hasStar := false
+	for _, ctrl := range o.EnableControllers {
+		if ctrl == name {
+			return true
+		}
+		if ctrl == "-"+name {
+			return false
+		}
+		if ctrl == "*" {
+			hasStar = true
+		}
+	}
+	return hasStar
+}
@@ -0,0 +1,145 @@
+package options
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+)
+
+var allControllers = []string{"deployment", "dns"}
+
+func TestIsControllerEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		enable  []string
+		disable []string
+		want    map[string]bool
+	}{
+		{
+			name:   "star enables all",
+			enable: []string{"*"},
+			want:   map[string]bool{"deployment": true, "dns": true},
+		},
+		{
+			name:   "named only",
+			enable: []string{"dns"},
+			want:   map[string]bool{"deployment": false, "dns": true},
+		},
+		{
+			name:   "star with negation",
+			enable: []string{"-dns", "*"},
+			want:   map[string]bool{"deployment": true, "dns": false},
+		},
+		{
+			name:   "first item wins",
+			enable: []string{"dns", "-dns"},
+			want:   map[string]bool{"deployment": false, "dns": true},
+		},
+		{
+			name:    "disable takes precedence",
+			enable:  []string{"*", "deployment"},
+			disable: []string{"deployment"},
+			want:    map[string]bool{"deployment": false, "dns": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Options{EnableControllers: tt.enable, DisableControllers: tt.disable}
+			for name, want := range tt.want {
+				if got := o.IsControllerEnabled(name); got != want {
+					t.Errorf("IsControllerEnabled(%q) = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateControllers(t *testing.T) {
+	tests := []struct {
+		name    string
+		enable  []string
+		disable []string
+		wantErr bool
+	}{
+		{name: "known", enable: []string{"*", "-dns"}, disable: []string{"deployment"}},
+		{name: "unknown enable", enable: []string{"foo"}, wantErr: true},
+		{name: "unknown negation", enable: []string{"*", "-foo"}, wantErr: true},
+		{name: "unknown disable", enable: []string{"*"}, disable: []string{"foo"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOptions()
+			o.EnableControllers, o.DisableControllers = tt.enable, tt.disable
+			if err := o.Validate(allControllers); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompleteFlagsOverrideConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	config := `apiVersion: config.bootstrapping.karmada.io/v1alpha1
+kind: CustomControllerManagerConfiguration
+leaderElection:
+  resourceNamespace: from-file
+clientConnection:
+  qps: 50
+  burst: 100
+controllers: ["-dns", "*"]
+dns:
+  configMapName: coredns-custom
+  syncInterval: 30s
+tracing:
+  endpoint: otel-collector:4317
+`
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	o := NewOptions()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.AddGoFlag(&flag.Flag{Name: "kubeconfig", Value: new(stringValue)})
+	o.AddFlags(flags, allControllers)
+	if err := flags.Parse([]string{"--config", file, "--dns-sync-interval", "10s", "--enable-controllers", "dns"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Complete(flags); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Validate(allControllers); err != nil {
+		t.Fatal(err)
+	}
+
+	if o.LeaderElection.ResourceNamespace != "from-file" {
+		t.Errorf("ResourceNamespace = %q, want %q", o.LeaderElection.ResourceNamespace, "from-file")
+	}
+	if o.ClientConnection.QPS != 50 || o.ClientConnection.Burst != 100 {
+		t.Errorf("ClientConnection = %v/%v, want 50/100", o.ClientConnection.QPS, o.ClientConnection.Burst)
+	}
+	if o.DNS.ConfigMapName != "coredns-custom" {
+		t.Errorf("DNS.ConfigMapName = %q, want %q", o.DNS.ConfigMapName, "coredns-custom")
+	}
+	if o.DNS.SyncInterval.Duration != 10*time.Second {
+		t.Errorf("DNS.SyncInterval = %v, want flag value 10s", o.DNS.SyncInterval.Duration)
+	}
+	if o.Tracing.Endpoint != "otel-collector:4317" || o.Tracing.SamplingRatePerMillion != tracing.DefaultSamplingRatePerMillion {
+		t.Errorf("Tracing = %+v, want endpoint from file and default sampling rate", o.Tracing)
+	}
+	if o.IsControllerEnabled("deployment") || !o.IsControllerEnabled("dns") {
+		t.Errorf("EnableControllers = %v, want flag value [dns]", o.EnableControllers)
+	}
+}
+
+type stringValue string
+
+func (s *stringValue) Set(v string) error { *s = stringValue(v); return nil }
+func (s *stringValue) String() string     { return string(*s) }
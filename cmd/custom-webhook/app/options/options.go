@@ -1,7 +1,17 @@
 package options
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	componentbaseconfig "k8s.io/component-base/config"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
 const (
@@ -9,6 +19,8 @@ const (
 	defaultPort          = 8443
 	defaultCertDir       = "./manifests/webhook/test-certs"
 	defaultTLSMinVersion = "1.3"
+	// defaultClusterRequestTimeout is the default timeout of the requests sent through the cluster proxy.
+	defaultClusterRequestTimeout = 60 * time.Second
 )
 
 type Options struct {
@@ -20,10 +32,30 @@ type Options struct {
 	TLSMinVersion          string
 	MetricsBindAddress     string
 	HealthProbeBindAddress string
+	// ClientConnection specifies the QPS, burst and content type of the clients talking to the karmada apiserver.
+	ClientConnection componentbaseconfig.ClientConnectionConfiguration
+	// ClusterRequestTimeout is the timeout of a single request sent to a member cluster through the karmada cluster proxy.
+	ClusterRequestTimeout time.Duration
+	// Tracing holds the configuration of the OTLP trace exporter.
+	Tracing tracing.Options
 }
 
 func NewOptions() *Options {
-	return &Options{}
+	return &Options{
+		ClientConnection: util.DefaultClientConnection(),
+		Tracing:          tracing.Options{SamplingRatePerMillion: tracing.DefaultSamplingRatePerMillion},
+	}
+}
+
+// Validate checks Options and return a slice of found errs.
+func (o *Options) Validate() error {
+	if err := util.ValidateClientConnection(o.ClientConnection); err != nil {
+		return err
+	}
+	if o.ClusterRequestTimeout <= 0 {
+		return fmt.Errorf("cluster-request-timeout must be greater than 0, got %v", o.ClusterRequestTimeout)
+	}
+	return tracingapi.ValidateTracingConfiguration(o.Tracing.Configuration(), nil, field.NewPath("tracing")).ToAggregate()
 }
 
 func (o *Options) AddFlags(flags *pflag.FlagSet) {
@@ -40,4 +72,8 @@ func (o *Options) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.TLSMinVersion, "tls-min-version", defaultTLSMinVersion, "Minimum TLS version supported. Possible values: 1.0, 1.1, 1.2, 1.3.")
 	flags.StringVar(&o.MetricsBindAddress, "metrics-bind-address", ":8080", "The TCP address that the controller should bind to for serving prometheus metrics(e.g. 127.0.0.1:8088, :8088)")
 	flags.StringVar(&o.HealthProbeBindAddress, "health-probe-bind-address", ":8000", "The TCP address that the controller should bind to for serving health probes(e.g. 127.0.0.1:8000, :8000)")
+	flags.DurationVar(&o.ClusterRequestTimeout, "cluster-request-timeout", defaultClusterRequestTimeout, "The timeout of a single request sent to a member cluster through the karmada cluster proxy.")
+	util.AddClientConnectionFlags(flags, &o.ClientConnection)
+	o.Tracing.AddFlags(flags)
+	features.FeatureGate.AddFlag(flags)
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -16,12 +18,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/prodanlabs/karmada-examples/cmd/custom-webhook/app/options"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+	"github.com/prodanlabs/karmada-examples/pkg/util"
 	"github.com/prodanlabs/karmada-examples/pkg/webhook/namespace"
 )
 
 const (
 	CheckEndpointHealthz = "healthz"
 	CheckEndpointReadyz  = "readyz"
+	CheckKarmadaAPIs     = "karmada-apis"
+	CheckInformerSync    = "informer-sync"
+	CheckServingCert     = "serving-cert"
 )
 
 var aggregatedScheme = runtime.NewScheme()
@@ -40,6 +49,9 @@ func NewWebhookCommand(ctx context.Context) *cobra.Command {
 		Use:  "karmada-webhook",
 		Long: `Start a karmada webhook server`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
 			if err := Run(ctx, opts); err != nil {
 				return err
 			}
@@ -71,6 +83,13 @@ func Run(ctx context.Context, opts *options.Options) error {
 	if err != nil {
 		return err
 	}
+	util.SetupKubeConfig(config, opts.ClientConnection)
+
+	tp, err := tracing.Setup(ctx, "karmada-webhook", opts.Tracing.Configuration(), config)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %v", err)
+	}
+	defer tracing.Shutdown(tp)
 
 	hookManager, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme: NewSchema(),
@@ -96,11 +115,28 @@ func Run(ctx context.Context, opts *options.Options) error {
 	if err := hookManager.AddReadyzCheck(CheckEndpointReadyz, healthz.Ping); err != nil {
 		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckEndpointReadyz, err)
 	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := hookManager.AddReadyzCheck(CheckKarmadaAPIs, util.APIResourcesChecker(discoveryClient, map[schema.GroupVersion][]string{
+		clusterv1alpha1.SchemeGroupVersion: {"clusters"},
+	})); err != nil {
+		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckKarmadaAPIs, err)
+	}
+	if err := hookManager.AddReadyzCheck(CheckInformerSync, util.CacheSyncChecker(hookManager.GetCache())); err != nil {
+		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckInformerSync, err)
+	}
+	if err := hookManager.AddReadyzCheck(CheckServingCert, util.ServingCertChecker(opts.CertDir, opts.CertName, opts.KeyName)); err != nil {
+		return fmt.Errorf("failed to add %q health check endpoint: %v", CheckServingCert, err)
+	}
+
+	metrics.RecordFeatureGates(features.FeatureGate)
 
 	klog.Info("registering webhooks to the webhook server")
 	hookServer := hookManager.GetWebhookServer()
 
-	hookServer.Register("/validate-namespace", &webhook.Admission{Handler: namespace.NewValidatingAdmission(hookManager)})
+	hookServer.Register("/validate-namespace", &webhook.Admission{Handler: namespace.NewValidatingAdmission(hookManager, opts.ClientConnection, opts.ClusterRequestTimeout)})
 
 	// blocks until the context is done.
 	if err := hookManager.Start(ctx); err != nil {
@@ -0,0 +1,5 @@
+// Package v1alpha1 is the v1alpha1 version of the bootstrapping API, it holds
+// the BootstrapPolicy which drives the deployment controller.
+// +k8s:deepcopy-gen=package
+// +groupName=bootstrapping.karmada.io
+package v1alpha1
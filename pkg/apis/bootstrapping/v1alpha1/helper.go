@@ -0,0 +1,35 @@
+package v1alpha1
+
+import "time"
+
+// DistributionMode returns the mode of the policy, Work when it is not set.
+func (s *BootstrapPolicySpec) DistributionMode() DistributionMode {
+	if s.Mode == "" {
+		return DistributionModeWork
+	}
+	return s.Mode
+}
+
+// SchedulingType returns the replica scheduling type of the placement, Divided when it is not set.
+func (p *Placement) SchedulingType() ReplicaSchedulingType {
+	if p.ReplicaScheduling == nil || p.ReplicaScheduling.Type == "" {
+		return ReplicaSchedulingTypeDivided
+	}
+	return p.ReplicaScheduling.Type
+}
+
+// OnFailure returns the failure policy of the rollout, Halt when it is not set.
+func (r *RolloutStrategy) OnFailure() RolloutFailurePolicy {
+	if r.FailurePolicy == "" {
+		return RolloutFailurePolicyHalt
+	}
+	return r.FailurePolicy
+}
+
+// ProgressDeadline returns the time a wave has to roll out, 10 minutes when it is not set.
+func (r *RolloutStrategy) ProgressDeadline() time.Duration {
+	if r.ProgressDeadlineSeconds == nil {
+		return 10 * time.Minute
+	}
+	return time.Duration(*r.ProgressDeadlineSeconds) * time.Second
+}
@@ -0,0 +1,409 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DistributionMode is the way the selected resources are distributed to the member clusters.
+type DistributionMode string
+
+const (
+	// DistributionModeWork writes a Work per member cluster directly into its execution namespace.
+	DistributionModeWork DistributionMode = "Work"
+	// DistributionModePropagationPolicy creates a PropagationPolicy and leaves the scheduling to karmada.
+	DistributionModePropagationPolicy DistributionMode = "PropagationPolicy"
+)
+
+// ReplicaSchedulingType is the way the replicas of a resource are scheduled to the target clusters.
+type ReplicaSchedulingType string
+
+const (
+	// ReplicaSchedulingTypeDuplicated runs the whole replicas of the resource in every target cluster.
+	ReplicaSchedulingTypeDuplicated ReplicaSchedulingType = "Duplicated"
+	// ReplicaSchedulingTypeDivided divides the replicas of the resource between the target clusters.
+	ReplicaSchedulingTypeDivided ReplicaSchedulingType = "Divided"
+)
+
+// DynamicWeightFactor is the factor karmada computes the weight of a target cluster from.
+type DynamicWeightFactor string
+
+const (
+	// DynamicWeightByAvailableReplicas weighs a target cluster by the replicas it can still run.
+	DynamicWeightByAvailableReplicas DynamicWeightFactor = "AvailableReplicas"
+)
+
+// SpreadFieldValue is a cluster field the target clusters are spread over.
+type SpreadFieldValue string
+
+const (
+	// SpreadByFieldCluster spreads the resources over the clusters.
+	SpreadByFieldCluster SpreadFieldValue = "cluster"
+	// SpreadByFieldRegion spreads the resources over the regions of the clusters.
+	SpreadByFieldRegion SpreadFieldValue = "region"
+	// SpreadByFieldZone spreads the resources over the zones of the clusters.
+	SpreadByFieldZone SpreadFieldValue = "zone"
+	// SpreadByFieldProvider spreads the resources over the providers of the clusters.
+	SpreadByFieldProvider SpreadFieldValue = "provider"
+)
+
+// RolloutFailurePolicy is what a staged rollout does when a wave fails.
+type RolloutFailurePolicy string
+
+const (
+	// RolloutFailurePolicyHalt stops the rollout, the clusters already updated keep the new revision.
+	RolloutFailurePolicyHalt RolloutFailurePolicy = "Halt"
+	// RolloutFailurePolicyRollback reverts the clusters already updated to the revision they ran before.
+	RolloutFailurePolicyRollback RolloutFailurePolicy = "Rollback"
+)
+
+// RolloutPhase is the phase of the staged rollout of a resource.
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing is the phase of a rollout whose waves are being updated.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhaseComplete is the phase of a rollout whose waves are all rolled out.
+	RolloutPhaseComplete RolloutPhase = "Complete"
+	// RolloutPhaseHalted is the phase of a rollout stopped by a failed wave.
+	RolloutPhaseHalted RolloutPhase = "Halted"
+	// RolloutPhaseRolledBack is the phase of a rollout reverted after a failed wave.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+const (
+	// ConditionTypeApplied is True when every selected resource was distributed without error.
+	ConditionTypeApplied = "Applied"
+
+	// ReasonApplySucceeded is the reason of the Applied condition when every resource was distributed.
+	ReasonApplySucceeded = "ApplySucceeded"
+	// ReasonApplyFailed is the reason of the Applied condition when at least one resource failed.
+	ReasonApplyFailed = "ApplyFailed"
+	// ReasonInvalidSpec is the reason of the Applied condition when the spec does not pass validation.
+	ReasonInvalidSpec = "InvalidSpec"
+
+	// ConditionTypeRolledOut is True when the resources distributed as Works are
+	// applied and rolled out in every member cluster.
+	ConditionTypeRolledOut = "RolledOut"
+
+	// ReasonRolloutComplete is the reason of the RolledOut condition when every Work is rolled out.
+	ReasonRolloutComplete = "RolloutComplete"
+	// ReasonRolloutInProgress is the reason of the RolledOut condition when at least one Work is not rolled out yet.
+	ReasonRolloutInProgress = "RolloutInProgress"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=bp
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BootstrapPolicy distributes the selected resources of its namespace to the member clusters.
+// It replaces the "bootstrapping.karmada.io/deployments-*" annotations.
+type BootstrapPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the desired distribution.
+	Spec BootstrapPolicySpec `json:"spec"`
+
+	// Status represents the most recently observed distribution.
+	// +optional
+	Status BootstrapPolicyStatus `json:"status,omitempty"`
+}
+
+// BootstrapPolicySpec represents the desired distribution of a BootstrapPolicy.
+type BootstrapPolicySpec struct {
+	// ResourceSelector selects the resources of the policy's namespace.
+	ResourceSelector ResourceSelector `json:"resourceSelector"`
+
+	// Placement describes the member clusters the resources are distributed to.
+	// +optional
+	Placement Placement `json:"placement,omitempty"`
+
+	// Mode is the way the resources are distributed, Work or PropagationPolicy.
+	// Defaults to Work.
+	// +kubebuilder:validation:Enum=Work;PropagationPolicy
+	// +optional
+	Mode DistributionMode `json:"mode,omitempty"`
+
+	// Rollout rolls the changes of the resources out to the target clusters in
+	// waves, in Work mode. Every target cluster is updated at once when it is not set.
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
+	// PropagateDeps propagates the ConfigMaps, Secrets, ServiceAccount and
+	// PersistentVolumeClaims the pod templates of the resources reference to
+	// their target clusters, they are removed with the last resource using them.
+	// +optional
+	PropagateDeps bool `json:"propagateDeps,omitempty"`
+}
+
+// ResourceSelector selects the resources of the policy's namespace.
+type ResourceSelector struct {
+	// APIVersion of the selected resources.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the selected resources.
+	Kind string `json:"kind"`
+
+	// Name of the selected resource, all the resources of the kind match when empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// LabelSelector restricts the selected resources to the ones whose labels match.
+	// It is ignored when Name is set.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// Placement describes the member clusters the resources are distributed to.
+// A member cluster is a target when it is listed in ClusterNames and its labels
+// match ClusterSelector, every member cluster is a target when both are empty.
+type Placement struct {
+	// ClusterNames is the list of the target member clusters.
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+
+	// ClusterSelector selects the target member clusters by their labels,
+	// e.g. region, env or provider.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// ClusterTolerations tolerates the taints of the member clusters, the clusters
+	// with a NoSchedule or NoExecute taint which is not tolerated are no targets.
+	// +optional
+	ClusterTolerations []corev1.Toleration `json:"clusterTolerations,omitempty"`
+
+	// ReplicaScheduling describes how the replicas of the resources are scheduled
+	// to the target clusters, in PropagationPolicy mode. Defaults to Divided with
+	// the same weight for every target cluster.
+	// +optional
+	ReplicaScheduling *ReplicaScheduling `json:"replicaScheduling,omitempty"`
+
+	// SpreadConstraints spread the resources over the target clusters, in
+	// PropagationPolicy mode.
+	// +optional
+	SpreadConstraints []SpreadConstraint `json:"spreadConstraints,omitempty"`
+}
+
+// ReplicaScheduling describes how the replicas of the resources are scheduled to the target clusters.
+type ReplicaScheduling struct {
+	// Type is the way the replicas are scheduled, Duplicated or Divided.
+	// Defaults to Divided.
+	// +kubebuilder:validation:Enum=Duplicated;Divided
+	// +optional
+	Type ReplicaSchedulingType `json:"type,omitempty"`
+
+	// StaticWeights divides the replicas by the weight of each target cluster,
+	// the clusters which are not listed weigh 1. It is exclusive with DynamicWeight
+	// and only applies to Divided.
+	// +optional
+	StaticWeights []ClusterWeight `json:"staticWeights,omitempty"`
+
+	// DynamicWeight divides the replicas by the weight karmada computes for each
+	// target cluster, e.g. AvailableReplicas. It only applies to Divided.
+	// +kubebuilder:validation:Enum=AvailableReplicas
+	// +optional
+	DynamicWeight DynamicWeightFactor `json:"dynamicWeight,omitempty"`
+}
+
+// ClusterWeight is the static weight of a target cluster.
+type ClusterWeight struct {
+	// ClusterName is the name of the target cluster.
+	ClusterName string `json:"clusterName"`
+
+	// Weight of the cluster.
+	// +kubebuilder:validation:Minimum=1
+	Weight int64 `json:"weight"`
+}
+
+// SpreadConstraint spreads the resources over the groups of the target clusters.
+type SpreadConstraint struct {
+	// SpreadByField is the cluster field the clusters are grouped by, one of
+	// cluster, region, zone or provider. It is exclusive with SpreadByLabel,
+	// the clusters are grouped by cluster when both are empty.
+	// +kubebuilder:validation:Enum=cluster;region;zone;provider
+	// +optional
+	SpreadByField SpreadFieldValue `json:"spreadByField,omitempty"`
+
+	// SpreadByLabel is the cluster label the clusters are grouped by.
+	// +optional
+	SpreadByLabel string `json:"spreadByLabel,omitempty"`
+
+	// MaxGroups is the maximum number of groups to spread over.
+	// +optional
+	MaxGroups int `json:"maxGroups,omitempty"`
+
+	// MinGroups is the minimum number of groups to spread over.
+	// +optional
+	MinGroups int `json:"minGroups,omitempty"`
+}
+
+// RolloutStrategy rolls the changes of the resources out to the target clusters
+// in waves. A wave is updated once the Works of the previous ones are applied
+// and their workloads available. Only the changes of the resources are staged,
+// the ones of the OverridePolicies and ClusterOverridePolicies are applied to
+// every target cluster at once.
+type RolloutStrategy struct {
+	// Waves is the ordered list of the waves, the target clusters listed in no
+	// wave are updated in a last wave. It is exclusive with MaxUnavailable.
+	// +optional
+	Waves []RolloutWave `json:"waves,omitempty"`
+
+	// MaxUnavailable is the number of target clusters updated at once, in the
+	// order of their names. It is exclusive with Waves.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUnavailable int32 `json:"maxUnavailable,omitempty"`
+
+	// FailurePolicy is what the rollout does when a wave fails, Halt or Rollback.
+	// A revision which was rolled back is not rolled out again until the
+	// resources change. Defaults to Halt.
+	// +kubebuilder:validation:Enum=Halt;Rollback
+	// +optional
+	FailurePolicy RolloutFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// ProgressDeadlineSeconds is the time a wave has to roll out before it fails.
+	// Defaults to 600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// RolloutWave is a group of target clusters updated together.
+type RolloutWave struct {
+	// Name of the wave, e.g. canary. Defaults to wave-<index>, from 1.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Clusters is the list of the member clusters of the wave.
+	Clusters []string `json:"clusters"`
+}
+
+// BootstrapPolicyStatus represents the most recently observed distribution of a BootstrapPolicy.
+type BootstrapPolicyStatus struct {
+	// ObservedGeneration is the generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions contain the different condition statuses of the policy.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Resources is the distribution of every selected resource.
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+}
+
+// ResourceStatus is the distribution of a selected resource.
+type ResourceStatus struct {
+	// APIVersion of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name of the resource.
+	Name string `json:"name"`
+
+	// Clusters is the list of the member clusters selected for the resource.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Works is the list of the Works written for the resource, in Work mode.
+	// +optional
+	Works []WorkReference `json:"works,omitempty"`
+
+	// PropagationPolicy is the name of the PropagationPolicy written for the
+	// resource, in PropagationPolicy mode.
+	// +optional
+	PropagationPolicy string `json:"propagationPolicy,omitempty"`
+
+	// Error is the last error met while distributing the resource.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// RolledOut is true when every Work of the resource is applied and its
+	// workload rolled out in the member cluster, in Work mode.
+	// +optional
+	RolledOut bool `json:"rolledOut,omitempty"`
+
+	// Rollout is the staged rollout of the resource, when the policy has a
+	// rollout strategy.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStatus is the staged rollout of a resource.
+type RolloutStatus struct {
+	// Revision is the revision of the resource being rolled out.
+	Revision string `json:"revision"`
+
+	// Phase of the rollout, Progressing, Complete, Halted or RolledBack.
+	Phase RolloutPhase `json:"phase"`
+
+	// Wave is the name of the wave being rolled out, or which failed.
+	// +optional
+	Wave string `json:"wave,omitempty"`
+
+	// Message explains the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkReference references a Work written for a member cluster.
+type WorkReference struct {
+	// Cluster is the name of the member cluster.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the execution namespace of the Work.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Work.
+	Name string `json:"name"`
+
+	// Revision is the revision of the workload of the Work.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// RevisionTime is when the Work was updated to its revision.
+	// +optional
+	RevisionTime *metav1.Time `json:"revisionTime,omitempty"`
+
+	// Applied is the status of the Applied condition of the Work, Unknown until
+	// karmada reports it.
+	// +optional
+	Applied metav1.ConditionStatus `json:"applied,omitempty"`
+
+	// Message explains why the Work is not applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Health is the health of the workload in the member cluster, Healthy,
+	// Unhealthy or Unknown.
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// Replicas, UpdatedReplicas, ReadyReplicas and AvailableReplicas are the
+	// replicas of the workload in the member cluster, for the kinds which have
+	// replicas.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// +optional
+	UpdatedReplicas *int32 `json:"updatedReplicas,omitempty"`
+	// +optional
+	ReadyReplicas *int32 `json:"readyReplicas,omitempty"`
+	// +optional
+	AvailableReplicas *int32 `json:"availableReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BootstrapPolicyList contains a list of BootstrapPolicy.
+type BootstrapPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BootstrapPolicy `json:"items"`
+}
@@ -0,0 +1,249 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateBootstrapPolicy ensures validation of the BootstrapPolicy struct.
+func ValidateBootstrapPolicy(policy *BootstrapPolicy) field.ErrorList {
+	return ValidateBootstrapPolicySpec(&policy.Spec, field.NewPath("spec"))
+}
+
+// ValidateBootstrapPolicySpec ensures validation of the BootstrapPolicySpec struct.
+func ValidateBootstrapPolicySpec(spec *BootstrapPolicySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, ValidateResourceSelector(&spec.ResourceSelector, fldPath.Child("resourceSelector"))...)
+	allErrs = append(allErrs, ValidatePlacement(&spec.Placement, fldPath.Child("placement"))...)
+	switch spec.Mode {
+	case "", DistributionModeWork, DistributionModePropagationPolicy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), spec.Mode,
+			[]string{string(DistributionModeWork), string(DistributionModePropagationPolicy)}))
+	}
+	if spec.DistributionMode() == DistributionModeWork {
+		// the Works are written whole to every target cluster, karmada schedules nothing.
+		if spec.Placement.ReplicaScheduling != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("placement", "replicaScheduling"), "only applies to PropagationPolicy mode"))
+		}
+		if len(spec.Placement.SpreadConstraints) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("placement", "spreadConstraints"), "only applies to PropagationPolicy mode"))
+		}
+	}
+	if spec.Rollout != nil {
+		if spec.DistributionMode() != DistributionModeWork {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollout"), "only applies to Work mode"))
+		}
+		allErrs = append(allErrs, ValidateRolloutStrategy(spec.Rollout, fldPath.Child("rollout"))...)
+	}
+	return allErrs
+}
+
+// ValidateResourceSelector ensures validation of the ResourceSelector struct.
+func ValidateResourceSelector(selector *ResourceSelector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// the kinds distributed are configured on the controller, a policy selecting
+	// another kind is accepted and selects nothing.
+	if gv, err := schema.ParseGroupVersion(selector.APIVersion); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiVersion"), selector.APIVersion, err.Error()))
+	} else if gv.Version == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiVersion"), ""))
+	}
+	if selector.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), ""))
+	}
+	if selector.Name != "" {
+		for _, msg := range validation.NameIsDNSSubdomain(selector.Name, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), selector.Name, msg))
+		}
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(selector.LabelSelector,
+		metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("labelSelector"))...)
+	return allErrs
+}
+
+// ValidatePlacement ensures validation of the Placement struct.
+func ValidatePlacement(placement *Placement, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.NewString()
+	for i, name := range placement.ClusterNames {
+		for _, msg := range utilvalidation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("clusterNames").Index(i), name, msg))
+		}
+		if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("clusterNames").Index(i), name))
+		}
+		seen.Insert(name)
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(placement.ClusterSelector,
+		metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("clusterSelector"))...)
+	for i := range placement.ClusterTolerations {
+		allErrs = append(allErrs, validateToleration(&placement.ClusterTolerations[i], fldPath.Child("clusterTolerations").Index(i))...)
+	}
+	if placement.ReplicaScheduling != nil {
+		allErrs = append(allErrs, ValidateReplicaScheduling(placement.ReplicaScheduling, fldPath.Child("replicaScheduling"))...)
+	}
+	for i := range placement.SpreadConstraints {
+		allErrs = append(allErrs, ValidateSpreadConstraint(&placement.SpreadConstraints[i], fldPath.Child("spreadConstraints").Index(i))...)
+	}
+	return allErrs
+}
+
+// validateToleration ensures validation of a cluster toleration, as the ones of the pods.
+func validateToleration(toleration *corev1.Toleration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if toleration.Key != "" {
+		for _, msg := range utilvalidation.IsQualifiedName(toleration.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), toleration.Key, msg))
+		}
+	}
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+		for _, msg := range utilvalidation.IsValidLabelValue(toleration.Value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), toleration.Value, msg))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), toleration.Value, "must be empty when operator is Exists"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("operator"), toleration.Operator,
+			[]string{string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists)}))
+	}
+	if toleration.Key == "" && toleration.Operator != corev1.TolerationOpExists {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("operator"), toleration.Operator, "must be Exists when key is empty"))
+	}
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("effect"), toleration.Effect,
+			[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+	}
+	return allErrs
+}
+
+// ValidateReplicaScheduling ensures validation of the ReplicaScheduling struct.
+func ValidateReplicaScheduling(scheduling *ReplicaScheduling, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch scheduling.Type {
+	case "", ReplicaSchedulingTypeDivided:
+	case ReplicaSchedulingTypeDuplicated:
+		if len(scheduling.StaticWeights) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("staticWeights"), "only applies to Divided"))
+		}
+		if scheduling.DynamicWeight != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("dynamicWeight"), "only applies to Divided"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), scheduling.Type,
+			[]string{string(ReplicaSchedulingTypeDuplicated), string(ReplicaSchedulingTypeDivided)}))
+	}
+	switch scheduling.DynamicWeight {
+	case "":
+	case DynamicWeightByAvailableReplicas:
+		if len(scheduling.StaticWeights) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("staticWeights"), "may not be set with dynamicWeight"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("dynamicWeight"), scheduling.DynamicWeight,
+			[]string{string(DynamicWeightByAvailableReplicas)}))
+	}
+	seen := sets.NewString()
+	for i, weight := range scheduling.StaticWeights {
+		weightPath := fldPath.Child("staticWeights").Index(i)
+		for _, msg := range utilvalidation.IsDNS1123Label(weight.ClusterName) {
+			allErrs = append(allErrs, field.Invalid(weightPath.Child("clusterName"), weight.ClusterName, msg))
+		}
+		if seen.Has(weight.ClusterName) {
+			allErrs = append(allErrs, field.Duplicate(weightPath.Child("clusterName"), weight.ClusterName))
+		}
+		seen.Insert(weight.ClusterName)
+		if weight.Weight < 1 {
+			allErrs = append(allErrs, field.Invalid(weightPath.Child("weight"), weight.Weight, "must be greater than zero"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateSpreadConstraint ensures validation of the SpreadConstraint struct.
+func ValidateSpreadConstraint(constraint *SpreadConstraint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if constraint.SpreadByField != "" && constraint.SpreadByLabel != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("spreadByLabel"), "may not be set with spreadByField"))
+	}
+	switch constraint.SpreadByField {
+	case "", SpreadByFieldCluster, SpreadByFieldRegion, SpreadByFieldZone, SpreadByFieldProvider:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("spreadByField"), constraint.SpreadByField,
+			[]string{string(SpreadByFieldCluster), string(SpreadByFieldRegion), string(SpreadByFieldZone), string(SpreadByFieldProvider)}))
+	}
+	if constraint.SpreadByLabel != "" {
+		for _, msg := range utilvalidation.IsQualifiedName(constraint.SpreadByLabel) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("spreadByLabel"), constraint.SpreadByLabel, msg))
+		}
+	}
+	if constraint.MinGroups < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minGroups"), constraint.MinGroups, "must not be negative"))
+	}
+	if constraint.MaxGroups < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxGroups"), constraint.MaxGroups, "must not be negative"))
+	} else if constraint.MaxGroups != 0 && constraint.MaxGroups < constraint.MinGroups {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxGroups"), constraint.MaxGroups, "must not be less than minGroups"))
+	}
+	return allErrs
+}
+
+// ValidateRolloutStrategy ensures validation of the RolloutStrategy struct.
+func ValidateRolloutStrategy(rollout *RolloutStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	case len(rollout.Waves) != 0 && rollout.MaxUnavailable != 0:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("maxUnavailable"), "may not be set with waves"))
+	case len(rollout.Waves) == 0 && rollout.MaxUnavailable == 0:
+		allErrs = append(allErrs, field.Required(fldPath, "one of waves or maxUnavailable is required"))
+	}
+	if rollout.MaxUnavailable < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), rollout.MaxUnavailable, "must not be negative"))
+	}
+	waveNames, clusters := sets.NewString(), sets.NewString()
+	for i, wave := range rollout.Waves {
+		wavePath := fldPath.Child("waves").Index(i)
+		if wave.Name != "" {
+			for _, msg := range utilvalidation.IsDNS1123Label(wave.Name) {
+				allErrs = append(allErrs, field.Invalid(wavePath.Child("name"), wave.Name, msg))
+			}
+			if waveNames.Has(wave.Name) {
+				allErrs = append(allErrs, field.Duplicate(wavePath.Child("name"), wave.Name))
+			}
+			waveNames.Insert(wave.Name)
+		}
+		if len(wave.Clusters) == 0 {
+			allErrs = append(allErrs, field.Required(wavePath.Child("clusters"), ""))
+		}
+		for j, name := range wave.Clusters {
+			for _, msg := range utilvalidation.IsDNS1123Label(name) {
+				allErrs = append(allErrs, field.Invalid(wavePath.Child("clusters").Index(j), name, msg))
+			}
+			// a cluster belongs to a single wave.
+			if clusters.Has(name) {
+				allErrs = append(allErrs, field.Duplicate(wavePath.Child("clusters").Index(j), name))
+			}
+			clusters.Insert(name)
+		}
+	}
+	switch rollout.FailurePolicy {
+	case "", RolloutFailurePolicyHalt, RolloutFailurePolicyRollback:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("failurePolicy"), rollout.FailurePolicy,
+			[]string{string(RolloutFailurePolicyHalt), string(RolloutFailurePolicyRollback)}))
+	}
+	if rollout.ProgressDeadlineSeconds != nil && *rollout.ProgressDeadlineSeconds < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadlineSeconds"), *rollout.ProgressDeadlineSeconds, "must be greater than zero"))
+	}
+	return allErrs
+}
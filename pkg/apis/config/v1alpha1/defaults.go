@@ -0,0 +1,108 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+const (
+	// DefaultLeaderElectionResourceName is the default name of the leader election lock.
+	DefaultLeaderElectionResourceName = "karmada-custom-controllers"
+	// DefaultLeaderElectionResourceNamespace is the default namespace of the leader election lock.
+	DefaultLeaderElectionResourceNamespace = "karmada-system"
+	// DefaultKubeQPS is the default QPS of the clients talking to the karmada apiserver.
+	DefaultKubeQPS = 5
+	// DefaultKubeBurst is the default burst of the clients talking to the karmada apiserver.
+	DefaultKubeBurst = 10
+	// DefaultDeploymentAnnotationPrefix is the default prefix of the deployment controller annotations.
+	DefaultDeploymentAnnotationPrefix = "bootstrapping.karmada.io"
+	// DefaultDNSConfigMapName is the default name of the CoreDNS ConfigMap.
+	DefaultDNSConfigMapName = "coredns"
+	// DefaultDNSSyncInterval is the default interval of the Corefile sync.
+	DefaultDNSSyncInterval = 5 * time.Second
+	// DefaultDNSClusterRequestTimeout is the default timeout of the requests sent through the cluster proxy.
+	DefaultDNSClusterRequestTimeout = 60 * time.Second
+	// DefaultDNSAnnotationPrefix is the default prefix of the global service annotation.
+	DefaultDNSAnnotationPrefix = "service.karmada.io"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&CustomControllerManagerConfiguration{}, func(obj interface{}) {
+		SetDefaults_CustomControllerManagerConfiguration(obj.(*CustomControllerManagerConfiguration))
+	})
+	return nil
+}
+
+// NewDefaultConfiguration returns a CustomControllerManagerConfiguration with all fields defaulted.
+func NewDefaultConfiguration() *CustomControllerManagerConfiguration {
+	cfg := &CustomControllerManagerConfiguration{}
+	SetDefaults_CustomControllerManagerConfiguration(cfg)
+	return cfg
+}
+
+// SetDefaults_CustomControllerManagerConfiguration sets additional defaults.
+func SetDefaults_CustomControllerManagerConfiguration(obj *CustomControllerManagerConfiguration) {
+	obj.APIVersion = SchemeGroupVersion.String()
+	obj.Kind = "CustomControllerManagerConfiguration"
+
+	if obj.LeaderElection.ResourceLock == "" {
+		obj.LeaderElection.ResourceLock = resourcelock.LeasesResourceLock
+	}
+	if obj.LeaderElection.ResourceName == "" {
+		obj.LeaderElection.ResourceName = DefaultLeaderElectionResourceName
+	}
+	if obj.LeaderElection.ResourceNamespace == "" {
+		obj.LeaderElection.ResourceNamespace = DefaultLeaderElectionResourceNamespace
+	}
+	componentbaseconfigv1alpha1.RecommendedDefaultLeaderElectionConfiguration(&obj.LeaderElection)
+
+	if obj.ClientConnection.QPS == 0 {
+		obj.ClientConnection.QPS = DefaultKubeQPS
+	}
+	if obj.ClientConnection.Burst == 0 {
+		obj.ClientConnection.Burst = DefaultKubeBurst
+	}
+	if obj.ClientConnection.ContentType == "" {
+		obj.ClientConnection.ContentType = runtime.ContentTypeJSON
+	}
+
+	if len(obj.Controllers) == 0 {
+		obj.Controllers = []string{"*"}
+	}
+
+	SetDefaults_DeploymentControllerConfiguration(&obj.Deployment)
+	SetDefaults_DNSControllerConfiguration(&obj.DNS)
+}
+
+// SetDefaults_DeploymentControllerConfiguration sets additional defaults.
+func SetDefaults_DeploymentControllerConfiguration(obj *DeploymentControllerConfiguration) {
+	if obj.AnnotationPrefix == "" {
+		obj.AnnotationPrefix = DefaultDeploymentAnnotationPrefix
+	}
+	if len(obj.Resources) == 0 {
+		obj.Resources = []metav1.GroupVersionKind{{Group: "apps", Version: "v1", Kind: "Deployment"}}
+	}
+}
+
+// SetDefaults_DNSControllerConfiguration sets additional defaults.
+func SetDefaults_DNSControllerConfiguration(obj *DNSControllerConfiguration) {
+	if obj.ConfigMapNamespace == "" {
+		obj.ConfigMapNamespace = metav1.NamespaceSystem
+	}
+	if obj.ConfigMapName == "" {
+		obj.ConfigMapName = DefaultDNSConfigMapName
+	}
+	if obj.SyncInterval.Duration == 0 {
+		obj.SyncInterval = metav1.Duration{Duration: DefaultDNSSyncInterval}
+	}
+	if obj.ClusterRequestTimeout.Duration == 0 {
+		obj.ClusterRequestTimeout = metav1.Duration{Duration: DefaultDNSClusterRequestTimeout}
+	}
+	if obj.AnnotationPrefix == "" {
+		obj.AnnotationPrefix = DefaultDNSAnnotationPrefix
+	}
+}
@@ -0,0 +1,4 @@
+// Package v1alpha1 is the v1alpha1 version of the custom-controller-manager component configuration.
+// +k8s:deepcopy-gen=package
+// +groupName=config.bootstrapping.karmada.io
+package v1alpha1
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CustomControllerManagerConfiguration contains the configuration of custom-controller-manager.
+type CustomControllerManagerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// LeaderElection defines the configuration of leader election client.
+	LeaderElection componentbaseconfigv1alpha1.LeaderElectionConfiguration `json:"leaderElection"`
+
+	// ClientConnection specifies the QPS, burst and content type of the clients talking to the karmada apiserver.
+	// Protobuf only applies to built-in types, karmada types always use JSON.
+	ClientConnection componentbaseconfigv1alpha1.ClientConnectionConfiguration `json:"clientConnection"`
+
+	// Controllers is the list of controllers to enable or disable.
+	// '*' means "all enabled by default controllers"
+	// 'foo' means "enable 'foo'"
+	// '-foo' means "disable 'foo'"
+	// first item for a particular name wins
+	// +optional
+	Controllers []string `json:"controllers,omitempty"`
+
+	// Deployment holds the configuration of the deployment controller.
+	Deployment DeploymentControllerConfiguration `json:"deployment"`
+
+	// DNS holds the configuration of the dns controller.
+	DNS DNSControllerConfiguration `json:"dns"`
+
+	// Tracing holds the configuration of the OTLP trace exporter, tracing is
+	// disabled when it is nil or the endpoint is empty.
+	// +optional
+	Tracing *tracingapi.TracingConfiguration `json:"tracing,omitempty"`
+}
+
+// DeploymentControllerConfiguration contains the configuration of the deployment controller.
+type DeploymentControllerConfiguration struct {
+	// AnnotationPrefix is the prefix of the deprecated annotations that drive the
+	// distribution of the deployments selected by no BootstrapPolicy,
+	// e.g. "<prefix>/deployments-global".
+	// Defaults to "bootstrapping.karmada.io".
+	// +optional
+	AnnotationPrefix string `json:"annotationPrefix,omitempty"`
+
+	// Resources is the list of the resource kinds the controller distributes,
+	// each of them is watched on its own, e.g. apps/v1 StatefulSet or v1 ConfigMap.
+	// Defaults to apps/v1 Deployment.
+	// +optional
+	Resources []metav1.GroupVersionKind `json:"resources,omitempty"`
+
+	// EvictionGracePeriod is how long a member cluster may stay NotReady, or
+	// tainted NoExecute, before the Works placed on it are evicted, with
+	// ClusterHealthAwarePlacement. Zero never evicts them.
+	// +optional
+	EvictionGracePeriod metav1.Duration `json:"evictionGracePeriod,omitempty"`
+}
+
+// DNSControllerConfiguration contains the configuration of the dns controller.
+type DNSControllerConfiguration struct {
+	// ConfigMapNamespace is the namespace of the CoreDNS ConfigMap.
+	// Defaults to "kube-system".
+	// +optional
+	ConfigMapNamespace string `json:"configMapNamespace,omitempty"`
+
+	// ConfigMapName is the name of the CoreDNS ConfigMap holding the Corefile.
+	// Defaults to "coredns".
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// SyncInterval is the interval at which the Corefile is synced.
+	// Defaults to 5s.
+	// +optional
+	SyncInterval metav1.Duration `json:"syncInterval,omitempty"`
+
+	// ClusterRequestTimeout is the timeout of a single request sent to a member
+	// cluster through the karmada cluster proxy.
+	// Defaults to 60s.
+	// +optional
+	ClusterRequestTimeout metav1.Duration `json:"clusterRequestTimeout,omitempty"`
+
+	// AnnotationPrefix is the prefix of the annotation that marks a global service,
+	// e.g. "<prefix>/global".
+	// Defaults to "service.karmada.io".
+	// +optional
+	AnnotationPrefix string `json:"annotationPrefix,omitempty"`
+}
@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+)
+
+// ValidateCustomControllerManagerConfiguration ensures validation of the CustomControllerManagerConfiguration struct.
+func ValidateCustomControllerManagerConfiguration(cfg *CustomControllerManagerConfiguration) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, ValidateLeaderElectionConfiguration(&cfg.LeaderElection, field.NewPath("leaderElection"))...)
+	allErrs = append(allErrs, ValidateClientConnectionConfiguration(&cfg.ClientConnection, field.NewPath("clientConnection"))...)
+	allErrs = append(allErrs, ValidateDeploymentControllerConfiguration(&cfg.Deployment, field.NewPath("deployment"))...)
+	allErrs = append(allErrs, ValidateDNSControllerConfiguration(&cfg.DNS, field.NewPath("dns"))...)
+	allErrs = append(allErrs, tracingapi.ValidateTracingConfiguration(cfg.Tracing, nil, field.NewPath("tracing"))...)
+
+	return allErrs
+}
+
+// ValidateLeaderElectionConfiguration ensures validation of the LeaderElectionConfiguration struct.
+func ValidateLeaderElectionConfiguration(cfg *componentbaseconfigv1alpha1.LeaderElectionConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cfg.LeaderElect == nil || !*cfg.LeaderElect {
+		return allErrs
+	}
+	if cfg.LeaseDuration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leaseDuration"), cfg.LeaseDuration, "must be greater than zero"))
+	}
+	if cfg.RenewDeadline.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("renewDeadline"), cfg.RenewDeadline, "must be greater than zero"))
+	}
+	if cfg.RetryPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retryPeriod"), cfg.RetryPeriod, "must be greater than zero"))
+	}
+	if cfg.LeaseDuration.Duration <= cfg.RenewDeadline.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leaseDuration"), cfg.LeaseDuration, "LeaseDuration must be greater than RenewDeadline"))
+	}
+	if len(cfg.ResourceName) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resourceName"), ""))
+	}
+	if len(cfg.ResourceNamespace) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resourceNamespace"), ""))
+	}
+	return allErrs
+}
+
+// ValidateClientConnectionConfiguration ensures validation of the ClientConnectionConfiguration struct.
+func ValidateClientConnectionConfiguration(cfg *componentbaseconfigv1alpha1.ClientConnectionConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cfg.QPS < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("qps"), cfg.QPS, "must be non-negative"))
+	}
+	if cfg.Burst < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("burst"), cfg.Burst, "must be non-negative"))
+	}
+	if cfg.ContentType != runtime.ContentTypeJSON && cfg.ContentType != runtime.ContentTypeProtobuf {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("contentType"), cfg.ContentType, []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf}))
+	}
+	return allErrs
+}
+
+// ValidateDeploymentControllerConfiguration ensures validation of the DeploymentControllerConfiguration struct.
+func ValidateDeploymentControllerConfiguration(cfg *DeploymentControllerConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := validateAnnotationPrefix(cfg.AnnotationPrefix, fldPath.Child("annotationPrefix"))
+
+	resourcesPath := fldPath.Child("resources")
+	if len(cfg.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(resourcesPath, "at least one resource kind is required"))
+	}
+	seen := map[metav1.GroupVersionKind]struct{}{}
+	for i, gvk := range cfg.Resources {
+		if gvk.Version == "" {
+			allErrs = append(allErrs, field.Required(resourcesPath.Index(i).Child("version"), ""))
+		}
+		if gvk.Kind == "" {
+			allErrs = append(allErrs, field.Required(resourcesPath.Index(i).Child("kind"), ""))
+		}
+		if _, ok := seen[gvk]; ok {
+			allErrs = append(allErrs, field.Duplicate(resourcesPath.Index(i), gvk))
+		}
+		seen[gvk] = struct{}{}
+	}
+	if cfg.EvictionGracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("evictionGracePeriod"), cfg.EvictionGracePeriod, "must not be negative"))
+	}
+	return allErrs
+}
+
+// ValidateDNSControllerConfiguration ensures validation of the DNSControllerConfiguration struct.
+func ValidateDNSControllerConfiguration(cfg *DNSControllerConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.ValidateNamespaceName(cfg.ConfigMapNamespace, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("configMapNamespace"), cfg.ConfigMapNamespace, msg))
+	}
+	for _, msg := range validation.NameIsDNSSubdomain(cfg.ConfigMapName, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("configMapName"), cfg.ConfigMapName, msg))
+	}
+	if cfg.SyncInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("syncInterval"), cfg.SyncInterval, "must be greater than zero"))
+	}
+	if cfg.ClusterRequestTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("clusterRequestTimeout"), cfg.ClusterRequestTimeout, "must be greater than zero"))
+	}
+	allErrs = append(allErrs, validateAnnotationPrefix(cfg.AnnotationPrefix, fldPath.Child("annotationPrefix"))...)
+	return allErrs
+}
+
+// validateAnnotationPrefix checks the prefix forms a valid qualified annotation key.
+func validateAnnotationPrefix(prefix string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range utilvalidation.IsDNS1123Subdomain(prefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath, prefix, msg))
+	}
+	return allErrs
+}
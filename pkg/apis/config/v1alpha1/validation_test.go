@@ -0,0 +1,80 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCustomControllerManagerConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *CustomControllerManagerConfiguration)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {},
+		},
+		{
+			name: "lease duration not greater than renew deadline",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.LeaderElection.LeaseDuration = metav1.Duration{Duration: 5 * time.Second}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative qps",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.ClientConnection.QPS = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid configmap name",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.DNS.ConfigMapName = "Core_DNS"
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero sync interval",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.DNS.SyncInterval = metav1.Duration{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid annotation prefix",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.Deployment.AnnotationPrefix = "bootstrapping.karmada.io/"
+			},
+			wantErr: true,
+		},
+		{
+			name: "resource without kind",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.Deployment.Resources = append(cfg.Deployment.Resources, metav1.GroupVersionKind{Version: "v1"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate resource",
+			mutate: func(cfg *CustomControllerManagerConfiguration) {
+				cfg.Deployment.Resources = append(cfg.Deployment.Resources, cfg.Deployment.Resources[0])
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfiguration()
+			tt.mutate(cfg)
+			if errs := ValidateCustomControllerManagerConfiguration(cfg); (len(errs) != 0) != tt.wantErr {
+				t.Errorf("ValidateCustomControllerManagerConfiguration() errs = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// fieldManager owns the fields of the Works and PropagationPolicies the controller applies.
+const fieldManager = "bootstrapping.karmada.io/deployment-controller"
+
+// legacyFieldManager owns the fields the controller wrote with Update before it
+// used server-side apply, the apiserver names it after the user agent.
+var legacyFieldManager = strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+
+// errNotOwned is returned when an object with the name of the one to apply
+// exists and was not written by the controller for the same resource.
+var errNotOwned = errors.New("not owned by the controller")
+
+// apply writes obj with server-side apply, the fields the controller no longer
+// sets are removed and the ones other managers changed are taken back.
+// existing is the current object, nil when there is none, its ownership must be
+// checked by the caller.
+func (c *Controller) apply(ctx context.Context, obj, existing client.Object) (controllerutil.OperationResult, error) {
+	if existing != nil {
+		if err := c.adoptLegacyFields(ctx, existing); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	}
+	if err := c.Client.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	switch {
+	case existing == nil:
+		return controllerutil.OperationResultCreated, nil
+	case obj.GetResourceVersion() != existing.GetResourceVersion():
+		return controllerutil.OperationResultUpdated, nil
+	default:
+		return controllerutil.OperationResultNone, nil
+	}
+}
+
+// adoptLegacyFields hands the fields the controller wrote with Update over to
+// its field manager, so that the apply removes the ones it no longer sets. It
+// is a no-op once the controller applied the object.
+func (c *Controller) adoptLegacyFields(ctx context.Context, obj client.Object) error {
+	managedFields, adopted := adoptedManagedFields(obj.GetManagedFields())
+	if !adopted {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	obj.SetManagedFields(managedFields)
+	if err := c.Client.Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Adopt the fields of %s/%s written by %q.", obj.GetNamespace(), obj.GetName(), legacyFieldManager)
+	return nil
+}
+
+// adoptedManagedFields returns the managed fields with the entry of the legacy
+// field manager turned into an apply of the controller's, false when there is
+// nothing to adopt.
+func adoptedManagedFields(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, bool) {
+	for _, entry := range entries {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return nil, false
+		}
+	}
+	for i, entry := range entries {
+		if entry.Manager != legacyFieldManager || entry.Operation != metav1.ManagedFieldsOperationUpdate || entry.Subresource != "" {
+			continue
+		}
+		adopted := make([]metav1.ManagedFieldsEntry, len(entries))
+		for j := range entries {
+			entries[j].DeepCopyInto(&adopted[j])
+		}
+		adopted[i].Manager = fieldManager
+		adopted[i].Operation = metav1.ManagedFieldsOperationApply
+		return adopted, true
+	}
+	return nil, false
+}
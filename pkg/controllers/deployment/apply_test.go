@@ -0,0 +1,58 @@
+package deployment
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdoptedManagedFields(t *testing.T) {
+	legacyUpdate := metav1.ManagedFieldsEntry{Manager: legacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate}
+	legacyStatus := metav1.ManagedFieldsEntry{Manager: legacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"}
+	otherUpdate := metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate}
+	applied := metav1.ManagedFieldsEntry{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationApply}
+
+	tests := []struct {
+		name        string
+		entries     []metav1.ManagedFieldsEntry
+		wantAdopted bool
+	}{
+		{
+			name:        "written with update",
+			entries:     []metav1.ManagedFieldsEntry{otherUpdate, legacyStatus, legacyUpdate},
+			wantAdopted: true,
+		},
+		{
+			name:    "already applied",
+			entries: []metav1.ManagedFieldsEntry{legacyUpdate, applied},
+		},
+		{
+			name:    "written by others",
+			entries: []metav1.ManagedFieldsEntry{otherUpdate, legacyStatus},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, adopted := adoptedManagedFields(tt.entries)
+			if adopted != tt.wantAdopted {
+				t.Fatalf("adopted = %v, want %v", adopted, tt.wantAdopted)
+			}
+			if !adopted {
+				return
+			}
+			if len(got) != len(tt.entries) {
+				t.Fatalf("got %d entries, want %d", len(got), len(tt.entries))
+			}
+			if got[0] != tt.entries[0] || got[1] != tt.entries[1] {
+				t.Errorf("the other entries changed: %v", got)
+			}
+			if got[2].Manager != fieldManager || got[2].Operation != metav1.ManagedFieldsOperationApply {
+				t.Errorf("entry = %s/%s, want %s/%s", got[2].Manager, got[2].Operation, fieldManager, metav1.ManagedFieldsOperationApply)
+			}
+			if tt.entries[2].Manager != legacyFieldManager {
+				t.Errorf("the managed fields passed in were modified")
+			}
+		})
+	}
+}
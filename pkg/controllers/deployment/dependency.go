@@ -0,0 +1,491 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+)
+
+// The dependencies of an object are the ConfigMaps, Secrets, ServiceAccount and
+// PersistentVolumeClaims its pod template references. Each of them is written
+// in a Work per cluster shared by the objects depending on it, every one of them
+// labels the Work, and the Work is deleted with the last label.
+
+const (
+	// labelDependency marks the Works which hold a dependency, it lives under its own
+	// prefix as the legacy owner label of a deployment named "dependency" would collide otherwise.
+	labelDependency = "dependency.bootstrapping.karmada.io/work"
+	// dependencyWorkSuffix suffixes the names of the Works of the dependencies, they
+	// would take the names of the Works of the ConfigMaps and Secrets distributed on their own otherwise.
+	dependencyWorkSuffix = "-dep"
+	// dependentLabelPrefix prefixes the labels of the objects depending on the
+	// dependency of a Work, the name of a label is hashed from its object.
+	dependentLabelPrefix = "dependent.bootstrapping.karmada.io/"
+)
+
+const (
+	kindConfigMap             = "ConfigMap"
+	kindSecret                = "Secret"
+	kindServiceAccount        = "ServiceAccount"
+	kindPersistentVolumeClaim = "PersistentVolumeClaim"
+)
+
+// dependencyKinds are the kinds of the dependencies, all of them are core/v1.
+var dependencyKinds = []string{kindConfigMap, kindSecret, kindServiceAccount, kindPersistentVolumeClaim}
+
+// dependency is an object of the namespace of a workload its pods reference.
+type dependency struct {
+	kind string
+	name string
+	// optional is set when every reference to the object is optional, the pods start without it.
+	optional bool
+}
+
+func (d dependency) String() string {
+	return d.kind + " " + d.name
+}
+
+// podSpec returns the pod spec of the workload, nil when it has none: its own
+// for a Pod, the one of its job template for a CronJob, the one of its pod
+// template otherwise.
+func podSpec(workload *unstructured.Unstructured) (*corev1.PodSpec, error) {
+	var fields []string
+	switch workload.GetKind() {
+	case "Pod":
+		fields = []string{"spec"}
+	case "CronJob":
+		fields = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		fields = []string{"spec", "template", "spec"}
+	}
+	raw, found, err := unstructured.NestedMap(workload.Object, fields...)
+	if err != nil || !found {
+		return nil, err
+	}
+	spec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// workloadDependencies returns the dependencies the pod spec of the workload references.
+func workloadDependencies(workload *unstructured.Unstructured) ([]dependency, error) {
+	spec, err := podSpec(workload)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+	return podDependencies(spec), nil
+}
+
+// podDependencies returns the dependencies the pod spec references, sorted by kind and name.
+func podDependencies(spec *corev1.PodSpec) []dependency {
+	var deps []dependency
+	add := func(kind, name string, optional *bool) {
+		if name == "" {
+			return
+		}
+		deps = append(deps, dependency{kind: kind, name: name, optional: optional != nil && *optional})
+	}
+
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			add(kindConfigMap, volume.ConfigMap.Name, volume.ConfigMap.Optional)
+		case volume.Secret != nil:
+			add(kindSecret, volume.Secret.SecretName, volume.Secret.Optional)
+		case volume.PersistentVolumeClaim != nil:
+			add(kindPersistentVolumeClaim, volume.PersistentVolumeClaim.ClaimName, nil)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(kindConfigMap, source.ConfigMap.Name, source.ConfigMap.Optional)
+				}
+				if source.Secret != nil {
+					add(kindSecret, source.Secret.Name, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	envs := func(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) {
+		for _, e := range env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add(kindConfigMap, ref.Name, ref.Optional)
+			}
+			if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+				add(kindSecret, ref.Name, ref.Optional)
+			}
+		}
+		for _, from := range envFrom {
+			if ref := from.ConfigMapRef; ref != nil {
+				add(kindConfigMap, ref.Name, ref.Optional)
+			}
+			if ref := from.SecretRef; ref != nil {
+				add(kindSecret, ref.Name, ref.Optional)
+			}
+		}
+	}
+	for _, container := range spec.InitContainers {
+		envs(container.Env, container.EnvFrom)
+	}
+	for _, container := range spec.Containers {
+		envs(container.Env, container.EnvFrom)
+	}
+	for _, container := range spec.EphemeralContainers {
+		envs(container.Env, container.EnvFrom)
+	}
+
+	for _, secret := range spec.ImagePullSecrets {
+		add(kindSecret, secret.Name, nil)
+	}
+	serviceAccount := spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = spec.DeprecatedServiceAccount
+	}
+	// every member cluster creates the default ServiceAccount of its namespaces.
+	if serviceAccount != "default" {
+		add(kindServiceAccount, serviceAccount, nil)
+	}
+	return mergeDependencies(deps)
+}
+
+// mergeDependencies returns the dependencies sorted by kind and name without
+// duplicates, a dependency is optional when every reference to it is.
+func mergeDependencies(deps ...[]dependency) []dependency {
+	merged := map[dependency]bool{}
+	for _, list := range deps {
+		for _, dep := range list {
+			key := dependency{kind: dep.kind, name: dep.name}
+			optional, seen := merged[key]
+			merged[key] = dep.optional && (optional || !seen)
+		}
+	}
+	result := make([]dependency, 0, len(merged))
+	for key, optional := range merged {
+		key.optional = optional
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].kind != result[j].kind {
+			return result[i].kind < result[j].kind
+		}
+		return result[i].name < result[j].name
+	})
+	return result
+}
+
+// dependentLabel returns the label the object sets on the Works of its dependencies.
+func (c *Controller) dependentLabel(key types.NamespacedName) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(controllerName(c.gvk) + "/" + key.Namespace + "/" + key.Name))
+	return fmt.Sprintf("%s%x", dependentLabelPrefix, hash.Sum64())
+}
+
+// dependencyWorkName returns the name of the Works of the dependency of the namespace.
+func dependencyWorkName(namespace string, dep dependency) string {
+	return names.GenerateWorkName(dep.kind, dep.name, namespace) + dependencyWorkSuffix
+}
+
+// syncDependencies writes the dependencies of the object to the clusters, the
+// ones the current workload of its Works references included: the pods of
+// a revision a staged rollout did not replace yet still need them. The object
+// is released from the Works of the other dependencies and clusters, it is
+// released from all of them when clusters is empty.
+func (c *Controller) syncDependencies(ctx context.Context, obj *unstructured.Unstructured, clusters []string) error {
+	keep := sets.NewString()
+	if len(clusters) == 0 {
+		return c.releaseDependencies(ctx, c.Client, client.ObjectKeyFromObject(obj), keep)
+	}
+
+	desired, err := workloadDependencies(obj)
+	if err != nil {
+		return fmt.Errorf("failed to read the pod template: %v", err)
+	}
+	works, err := c.targetWorks(ctx, obj, clusters)
+	if err != nil {
+		return err
+	}
+
+	// every dependency is read once, the Works of the ones which failed to be read are kept as they are.
+	workloads := map[dependency]*unstructured.Unstructured{}
+	failed := map[dependency]bool{}
+	var errs []error
+	for _, cluster := range clusters {
+		deps := desired
+		if work, ok := works[cluster]; ok {
+			if workload, err := workWorkload(work); err == nil {
+				current, err := workloadDependencies(workload)
+				if err != nil {
+					klog.V(2).Infof("Failed to read the pod template of work %s/%s: %v", work.Namespace, work.Name, err)
+				}
+				deps = mergeDependencies(desired, current)
+			}
+		}
+
+		workNamespace := names.GenerateExecutionSpaceName(cluster)
+		for _, dep := range deps {
+			key := dependency{kind: dep.kind, name: dep.name}
+			workload, read := workloads[key]
+			if !read && !failed[key] {
+				if workload, err = c.dependencyWorkload(ctx, obj, dep); err != nil {
+					failed[key] = true
+					errs = append(errs, fmt.Errorf("%s: %v", dep, err))
+				} else {
+					workloads[key] = workload
+				}
+			}
+			if failed[key] {
+				keep.Insert(workNamespace + "/" + dependencyWorkName(obj.GetNamespace(), dep))
+				continue
+			}
+			if workload == nil {
+				continue
+			}
+			keep.Insert(workNamespace + "/" + dependencyWorkName(obj.GetNamespace(), dep))
+			if err := c.applyDependencyWork(ctx, obj, cluster, dep, workload); err != nil {
+				errs = append(errs, fmt.Errorf("%s for cluster %s: %v", dep, cluster, err))
+			}
+		}
+	}
+
+	if err := c.releaseDependencies(ctx, c.Client, client.ObjectKeyFromObject(obj), keep); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// dependencyWorkload reads the dependency from the apiserver, the dependencies
+// are not cached. It returns nil when the dependency does not exist or is
+// issued by every member cluster on its own.
+func (c *Controller) dependencyWorkload(ctx context.Context, obj *unstructured.Unstructured, dep dependency) (*unstructured.Unstructured, error) {
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(dep.kind))
+	if err := c.apiReader.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: dep.name}, workload); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		klog.V(2).Infof("%s of namespace %q %s %q does not exist, skip.", dep, obj.GetNamespace(), c.gvk.Kind, obj.GetName())
+		if !dep.optional {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonDependencyNotFound,
+				"%s referenced by the pod template does not exist, it is not propagated", dep)
+		}
+		return nil, nil
+	}
+
+	unstructured.RemoveNestedField(workload.Object, "status")
+	switch dep.kind {
+	case kindSecret:
+		if secretType, _, _ := unstructured.NestedString(workload.Object, "type"); secretType == string(corev1.SecretTypeServiceAccountToken) {
+			klog.V(2).Infof("%s of namespace %q is a service account token, every member cluster issues its own.", dep, obj.GetNamespace())
+			return nil, nil
+		}
+	case kindServiceAccount:
+		// the token secrets of the ServiceAccount are issued by every member cluster.
+		unstructured.RemoveNestedField(workload.Object, "secrets")
+	case kindPersistentVolumeClaim:
+		// the claim is bound to a volume of the member cluster.
+		unstructured.RemoveNestedField(workload.Object, "spec", "volumeName")
+	}
+	return workload, nil
+}
+
+// applyDependencyWork writes the dependency in its Work for the cluster and
+// labels the Work with the object, the labels of the other objects depending
+// on it are kept. A Work of the same name which does not hold a dependency,
+// e.g. the one of a policy selecting the dependency itself, is left untouched.
+func (c *Controller) applyDependencyWork(ctx context.Context, obj *unstructured.Unstructured, cluster string, dep dependency,
+	workload *unstructured.Unstructured) error {
+	workNamespace := names.GenerateExecutionSpaceName(cluster)
+	workName := dependencyWorkName(obj.GetNamespace(), dep)
+	resource := workload.DeepCopy()
+	karmadautil.MergeLabel(resource, workv1alpha1.WorkNamespaceLabel, workNamespace)
+	karmadautil.MergeLabel(resource, workv1alpha1.WorkNameLabel, workName)
+	label := c.dependentLabel(client.ObjectKeyFromObject(obj))
+
+	var result controllerutil.OperationResult
+	var reader client.Reader = c.Client
+	// the labels are written with the resource version they were read at, a
+	// conflict means another object changed them meanwhile.
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		workMeta := metav1.ObjectMeta{
+			Name:       workName,
+			Namespace:  workNamespace,
+			Finalizers: []string{karmadautil.ExecutionControllerFinalizer},
+			Labels:     map[string]string{labelDependency: "true", label: "true"},
+		}
+		current := &workv1alpha1.Work{}
+		err := reader.Get(ctx, client.ObjectKey{Namespace: workNamespace, Name: workName}, current)
+		// the cache may be behind after a conflict, the Work is read from the apiserver from now on.
+		reader = c.apiReader
+		if err == nil {
+			if current.Labels[labelDependency] != "true" {
+				klog.V(2).Infof("Work %s/%s exists and does not hold a dependency, skip %s.", workNamespace, workName, dep)
+				return nil
+			}
+			if !current.DeletionTimestamp.IsZero() {
+				return fmt.Errorf("work %s/%s is being deleted", workNamespace, workName)
+			}
+			for key := range current.Labels {
+				if strings.HasPrefix(key, dependentLabelPrefix) {
+					workMeta.Labels[key] = "true"
+				}
+			}
+			workMeta.ResourceVersion = current.ResourceVersion
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		} else {
+			current = nil
+		}
+
+		if c.dryRun {
+			return c.dryRunWork(ctx, workMeta, resource)
+		}
+		work, err := newWork(workMeta, resource)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			// an apply would take the labels of another object creating the Work meanwhile.
+			if err := c.Client.Create(ctx, work, client.FieldOwner(fieldManager)); err != nil {
+				return err
+			}
+			result = controllerutil.OperationResultCreated
+			return nil
+		}
+		result, err = c.apply(ctx, work, current)
+		return err
+	})
+	if err != nil {
+		klog.Errorf("Failed to create/update dependency work %s/%s. Error: %v", workNamespace, workName, err)
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkSyncFailed,
+			"Failed to sync Work %s/%s of %s for cluster %s: %v", workNamespace, workName, dep, cluster, err)
+		return err
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		metrics.RecordWorkOperation(cluster, metrics.OperationCreated)
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonWorkCreated,
+			"Work %s/%s of %s created for cluster %s", workNamespace, workName, dep, cluster)
+	case controllerutil.OperationResultUpdated:
+		metrics.RecordWorkOperation(cluster, metrics.OperationUpdated)
+		klog.V(2).Infof("Update dependency work %s/%s successfully.", workNamespace, workName)
+	}
+	return nil
+}
+
+// releaseDependencies releases the object from the Works of its dependencies
+// outside of keep, a set of "namespace/name" of Works.
+func (c *Controller) releaseDependencies(ctx context.Context, reader client.Reader, key types.NamespacedName, keep sets.String) error {
+	label := c.dependentLabel(key)
+	works := &workv1alpha1.WorkList{}
+	if err := reader.List(ctx, works, client.HasLabels{labelDependency, label}); err != nil {
+		return fmt.Errorf("failed to list dependency works: %v", err)
+	}
+
+	var errs []error
+	for i := range works.Items {
+		work := &works.Items[i]
+		if keep.Has(work.Namespace+"/"+work.Name) || !work.DeletionTimestamp.IsZero() {
+			continue
+		}
+		cluster, err := names.GetClusterName(work.Namespace)
+		if err != nil {
+			continue
+		}
+		if c.dryRun {
+			klog.Infof("[dry-run] would release Work %s/%s of cluster %s from %s %s", work.Namespace, work.Name, cluster, c.gvk.Kind, key)
+			continue
+		}
+		if err := c.releaseDependencyWork(ctx, cluster, work, label); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", cluster, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// releaseDependencyWork removes the label of an object from the Work, the Work
+// is deleted when no other object depends on it.
+func (c *Controller) releaseDependencyWork(ctx context.Context, cluster string, work *workv1alpha1.Work, label string) error {
+	dependents := 0
+	for key := range work.Labels {
+		if key != label && strings.HasPrefix(key, dependentLabelPrefix) {
+			dependents++
+		}
+	}
+	if dependents != 0 {
+		patch := client.MergeFromWithOptions(work.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		delete(work.Labels, label)
+		return client.IgnoreNotFound(c.Client.Patch(ctx, work, patch))
+	}
+
+	// the preconditions fail when another object labeled the Work meanwhile.
+	if err := c.Client.Delete(ctx, work, client.Preconditions{UID: &work.UID, ResourceVersion: &work.ResourceVersion}); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	metrics.RecordWorkOperation(cluster, metrics.OperationDeleted)
+	klog.Infof("Delete dependency work %s/%s of cluster %q, no object depends on it anymore.", work.Namespace, work.Name, cluster)
+	return nil
+}
+
+// dependencyToObjects returns the map of the dependencies of the kind to the
+// distributed objects whose pod template references them.
+func (c *Controller) dependencyToObjects(kind string) handler.MapFunc {
+	return func(dep client.Object) []reconcile.Request {
+		list := c.newList()
+		if err := c.Client.List(context.TODO(), list, client.InNamespace(dep.GetNamespace())); err != nil {
+			klog.Errorf("Failed to list %s of namespace %q. error: %v", c.gvk.Kind, dep.GetNamespace(), err)
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range list.Items {
+			obj := &list.Items[i]
+			// only the distributed objects carry the finalizer.
+			if !controllerutil.ContainsFinalizer(obj, finalizer) {
+				continue
+			}
+			deps, err := workloadDependencies(obj)
+			if err != nil {
+				continue
+			}
+			for _, d := range deps {
+				if d.kind == kind && d.name == dep.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,177 @@
+package deployment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	"github.com/karmada-io/karmada/pkg/util/names"
+)
+
+func TestWorkloadDependencies(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"serviceAccountName": "nginx",
+		"imagePullSecrets":   []interface{}{map[string]interface{}{"name": "registry"}},
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "nginx-config"}},
+			map[string]interface{}{"name": "data", "persistentVolumeClaim": map[string]interface{}{"claimName": "nginx-data"}},
+			map[string]interface{}{"name": "projected", "projected": map[string]interface{}{"sources": []interface{}{
+				map[string]interface{}{"secret": map[string]interface{}{"name": "tls", "optional": true}},
+			}}},
+		},
+		"initContainers": []interface{}{map[string]interface{}{
+			"name":    "init",
+			"envFrom": []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": "nginx-config", "optional": true}}},
+		}},
+		"containers": []interface{}{map[string]interface{}{
+			"name": "nginx",
+			"env": []interface{}{map[string]interface{}{
+				"name":      "PASSWORD",
+				"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "credentials", "key": "password"}},
+			}},
+		}},
+	}
+	want := []dependency{
+		{kind: kindConfigMap, name: "nginx-config"},
+		{kind: kindPersistentVolumeClaim, name: "nginx-data"},
+		{kind: kindSecret, name: "credentials"},
+		{kind: kindSecret, name: "registry"},
+		{kind: kindSecret, name: "tls", optional: true},
+		{kind: kindServiceAccount, name: "nginx"},
+	}
+
+	tests := []struct {
+		name     string
+		workload map[string]interface{}
+		want     []dependency
+	}{
+		{
+			name:     "deployment",
+			workload: map[string]interface{}{"kind": "Deployment", "spec": map[string]interface{}{"template": map[string]interface{}{"spec": podSpec}}},
+			want:     want,
+		},
+		{
+			name: "cronjob",
+			workload: map[string]interface{}{"kind": "CronJob", "spec": map[string]interface{}{"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": podSpec}},
+			}}},
+			want: want,
+		},
+		{
+			name:     "pod with the default service account",
+			workload: map[string]interface{}{"kind": "Pod", "spec": map[string]interface{}{"serviceAccountName": "default"}},
+			want:     []dependency{},
+		},
+		{
+			name:     "no pod template",
+			workload: map[string]interface{}{"kind": "Service", "spec": map[string]interface{}{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := workloadDependencies(&unstructured.Unstructured{Object: tt.workload})
+			if err != nil {
+				t.Fatalf("workloadDependencies() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("workloadDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyWorkName(t *testing.T) {
+	configMaps := &Controller{gvk: corev1.SchemeGroupVersion.WithKind(kindConfigMap)}
+	dep := dependency{kind: kindConfigMap, name: "nginx-config"}
+	key := types.NamespacedName{Namespace: "default", Name: dep.name}
+	if name := dependencyWorkName(key.Namespace, dep); configMaps.workNames(key).Has(name) {
+		t.Errorf("dependencyWorkName() = %q, the name of the Work of the ConfigMap distributed on its own", name)
+	}
+}
+
+func TestDependencyWork(t *testing.T) {
+	c := newTestController()
+	c.Client = &applyClient{Client: c.Client}
+	dep := dependency{kind: kindConfigMap, name: "nginx-config"}
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("v1")
+	workload.SetKind(kindConfigMap)
+	workload.SetNamespace(nginx.Namespace)
+	workload.SetName(dep.name)
+
+	apache := types.NamespacedName{Namespace: nginx.Namespace, Name: "apache"}
+	for _, key := range []types.NamespacedName{nginx, apache} {
+		obj := c.newObject()
+		obj.SetNamespace(key.Namespace)
+		obj.SetName(key.Name)
+		if err := c.applyDependencyWork(context.TODO(), obj, "member1", dep, workload); err != nil {
+			t.Fatalf("applyDependencyWork() error = %v", err)
+		}
+	}
+	work := &workv1alpha1.Work{}
+	workKey := client.ObjectKey{Namespace: names.GenerateExecutionSpaceName("member1"), Name: dependencyWorkName(nginx.Namespace, dep)}
+	dependents := func() sets.String {
+		t.Helper()
+		if err := c.Client.Get(context.TODO(), workKey, work); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		labels := sets.NewString()
+		for key := range work.Labels {
+			labels.Insert(key)
+		}
+		return labels
+	}
+	if got := dependents(); !got.HasAll(c.dependentLabel(nginx), c.dependentLabel(apache)) {
+		t.Errorf("labels = %v, want both dependents", got.List())
+	}
+
+	if err := c.releaseDependencies(context.TODO(), c.Client, nginx, nil); err != nil {
+		t.Fatalf("releaseDependencies() error = %v", err)
+	}
+	if got := dependents(); got.Has(c.dependentLabel(nginx)) || !got.Has(c.dependentLabel(apache)) || !work.DeletionTimestamp.IsZero() {
+		t.Errorf("labels = %v, deleted = %v, want the Work kept for the other dependent", got.List(), !work.DeletionTimestamp.IsZero())
+	}
+
+	if err := c.releaseDependencies(context.TODO(), c.Client, apache, nil); err != nil {
+		t.Fatalf("releaseDependencies() error = %v", err)
+	}
+	// the finalizer of the execution controller holds the Work until karmada removed the dependency.
+	if err := c.Client.Get(context.TODO(), workKey, work); !apierrors.IsNotFound(err) && work.DeletionTimestamp.IsZero() {
+		t.Errorf("Get() error = %v, want the Work deleted with its last dependent", err)
+	}
+}
+
+func TestApplyDependencyWorkNotHeld(t *testing.T) {
+	dep := dependency{kind: kindConfigMap, name: "nginx-config"}
+	// the Work of a policy selecting the ConfigMap itself.
+	existing := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{
+		Namespace: names.GenerateExecutionSpaceName("member1"),
+		Name:      dependencyWorkName(nginx.Namespace, dep),
+		Labels:    map[string]string{"app": "config"},
+	}}
+	c := newTestController(existing)
+	applier := &applyClient{Client: c.Client}
+	c.Client = applier
+
+	obj := c.newObject()
+	obj.SetNamespace(nginx.Namespace)
+	obj.SetName(nginx.Name)
+	if err := c.applyDependencyWork(context.TODO(), obj, "member1", dep, &unstructured.Unstructured{}); err != nil {
+		t.Fatalf("applyDependencyWork() error = %v", err)
+	}
+	if err := c.releaseDependencies(context.TODO(), c.Client, nginx, nil); err != nil {
+		t.Fatalf("releaseDependencies() error = %v", err)
+	}
+	if len(applier.writes) != 0 {
+		t.Errorf("writes = %v, want the Work left untouched", applier.writes)
+	}
+}
@@ -2,251 +2,750 @@ package deployment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/karmada-io/karmada/pkg/util/helper"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
 	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
 	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
 	karmadautil "github.com/karmada-io/karmada/pkg/util"
 	"github.com/karmada-io/karmada/pkg/util/names"
+	"github.com/karmada-io/karmada/pkg/util/overridemanager"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
 )
 
 const (
 	ControllerName = "deployment-controller"
-)
 
-var workGVR = schema.GroupVersionResource{
-	Group:    workv1alpha1.GroupVersion.Group,
-	Version:  workv1alpha1.GroupVersion.Version,
-	Resource: "works",
-}
+	// finalizer is added to the distributed objects, it is released once their Works are gone from every execution namespace.
+	finalizer = "bootstrapping.karmada.io/work-cleanup"
+
+	annotationGlobal  = "deployments-global"
+	annotationMembers = "deployments-members"
+	annotationForce   = "deployments-force"
+	// annotationClusterSelector selects the member clusters by label, e.g. "region=east,env in (prod,staging)".
+	annotationClusterSelector = "deployments-cluster-selector"
+	// annotationClusterTolerations holds the JSON encoded tolerations of the object for the taints of the member clusters,
+	// e.g. '[{"key":"dedicated","operator":"Equal","value":"infra","effect":"NoSchedule"}]'.
+	annotationClusterTolerations = "cluster-tolerations"
+	// annotationPropagateDeps propagates the dependencies of a deployment distributed by the legacy annotations when "true".
+	annotationPropagateDeps = "deployments-propagate-deps"
+	// annotationStatus holds the JSON encoded status of an object distributed by the legacy annotations,
+	// the ones selected by a BootstrapPolicy report it on the policy.
+	annotationStatus = "deployments-status"
+)
 
 var _ reconcile.Reconciler = &Controller{}
 
-// Controller reconciles a ContainerSet object
+// Controller distributes the objects of a resource kind, Deployments by default.
 type Controller struct {
 	client.Client
-	scheme        *runtime.Scheme
-	recorder      record.EventRecorder
-	dynamicClient dynamic.Interface
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	// apiReader reads the Works from the apiserver when the cache may be stale.
+	apiReader client.Reader
+	config    configv1alpha1.DeploymentControllerConfiguration
+	// gvk is the kind of the objects distributed by the controller.
+	gvk schema.GroupVersionKind
+	// dryRun computes the Works and PropagationPolicies and logs the diff without writing anything.
+	dryRun bool
 }
 
 // Reconcile  The function does not differentiate between create, update or deletion events.
 // Instead it simply reads the state of the cluster at the time it is called.
-func (c *Controller) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	deployment := &appsv1.Deployment{}
+func (c *Controller) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "deployment.Reconcile", tracing.AttributeKind.String(c.gvk.Kind),
+		tracing.AttributeNamespace.String(request.Namespace), tracing.AttributeName.String(request.Name))
+	defer func() { tracing.End(span, err) }()
+
+	obj := c.newObject()
 	clusterList := &clusterv1alpha1.ClusterList{}
 
 	if err := c.Client.List(ctx, clusterList); err != nil {
 		klog.Errorf("Failed to list clusters, error: %v", err)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, err
 	}
 
-	if err := c.Client.Get(ctx, request.NamespacedName, deployment); err != nil {
+	if err := c.Client.Get(ctx, request.NamespacedName, obj); err != nil {
 		if apierrors.IsNotFound(err) {
+			// the objects distributed before the finalizer was introduced are cleaned up here.
 			klog.Warningf("Namespace %s %v", request.Namespace, err)
-			if err := c.removeWorks(request, clusterList.Items); err != nil {
-				klog.Errorf("delete namespace %q deployment %q work failed. err: %v", request.NamespacedName, request.Name, request.String(), err)
+			if _, err := c.removeWorks(ctx, request.NamespacedName, nil); err != nil {
+				klog.Errorf("delete namespace %q %s %q work failed. err: %v", request.Namespace, c.gvk.Kind, request.Name, err)
 				return ctrl.Result{}, err
 			}
-			return ctrl.Result{}, nil
+			if err := c.releaseDependencies(ctx, c.apiReader, request.NamespacedName, nil); err != nil {
+				klog.Errorf("Failed to release the dependencies of namespace %q %s %q. err: %v", request.Namespace, c.gvk.Kind, request.Name, err)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, c.syncPolicyStatus(ctx, request.NamespacedName, nil, nil)
 		}
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	clusters := c.skipClusters(deployment, clusterList.Items)
-	if len(clusters) == 0 {
-		return ctrl.Result{}, nil
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if result, err := c.cleanup(ctx, obj); err != nil || !result.IsZero() {
+			return result, err
+		}
+		return ctrl.Result{}, c.syncPolicyStatus(ctx, request.NamespacedName, nil, nil)
 	}
 
-	if !deployment.DeletionTimestamp.IsZero() {
-		if err := c.removeWorks(request, clusterList.Items); err != nil {
-			klog.Errorf("delete namespace %q deployment %q work failed. err: %v", request.NamespacedName, request.Name, request.String(), err)
+	policy, legacy, err := c.policyFor(ctx, obj)
+	if err != nil {
+		klog.Errorf("Failed to find the BootstrapPolicy of namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if policy == nil {
+		klog.Infof("namespace %q %s %q is selected by no BootstrapPolicy, nor distributed by annotations, skip.", obj.GetNamespace(), c.gvk.Kind, obj.GetName())
+		// an invalid cluster selector annotation is reported by an event, the Works are left as they are.
+		if c.hasInvalidLegacyPlacement(obj) {
+			return ctrl.Result{}, c.syncPolicyStatus(ctx, request.NamespacedName, nil, nil)
+		}
+		if err := c.removePropagationPolicy(ctx, obj); err != nil {
+			klog.Errorf("Failed to remove the PropagationPolicy of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
 			return ctrl.Result{Requeue: true}, err
 		}
-		return ctrl.Result{}, nil
+		if controllerutil.ContainsFinalizer(obj, finalizer) {
+			if result, err := c.cleanup(ctx, obj); err != nil || !result.IsZero() {
+				return result, err
+			}
+		} else if err := c.pruneWorks(ctx, obj, nil, nil); err != nil {
+			klog.Errorf("Failed to prune the works of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		} else if err := c.syncDependencies(ctx, obj, nil); err != nil {
+			klog.Errorf("Failed to release the dependencies of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		if err := c.syncLegacyStatus(ctx, obj, nil); err != nil {
+			klog.Errorf("Failed to sync the status of namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, c.syncPolicyStatus(ctx, request.NamespacedName, nil, nil)
 	}
 
-	if v, ok := deployment.GetAnnotations()["bootstrapping.karmada.io/deployments-force"]; ok && v != "true" {
-		c.buildPropagationPolicy(deployment, clusters)
-		return ctrl.Result{}, nil
+	if err := c.ensureFinalizer(ctx, obj); err != nil {
+		klog.Errorf("Failed to add the finalizer to namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{}, err
 	}
 
-	err := c.buildWorks(deployment, clusters)
+	status := &bootstrappingv1alpha1.ResourceStatus{
+		APIVersion: c.gvk.GroupVersion().String(),
+		Kind:       c.gvk.Kind,
+		Name:       obj.GetName(),
+	}
+	result, err = c.distribute(ctx, obj, policy, legacy, clusterList.Items, status)
 	if err != nil {
-		klog.Errorf("Failed to build work for namespace %s. Error: %v.", deployment.GetName(), err)
+		status.Error = err.Error()
+	}
+	if legacy {
+		if err := c.syncLegacyStatus(ctx, obj, status); err != nil {
+			klog.Errorf("Failed to sync the status of namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return result, err
+	}
+	if err := c.syncLegacyStatus(ctx, obj, nil); err != nil {
+		klog.Errorf("Failed to sync the status of namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err := c.syncPolicyStatus(ctx, request.NamespacedName, policy, status); err != nil {
+		klog.Errorf("Failed to sync the status of BootstrapPolicy %s/%s. error: %v", policy.Namespace, policy.Name, err)
 		return ctrl.Result{Requeue: true}, err
 	}
+	return result, err
+}
+
+// distribute distributes the object as the policy describes, status is
+// filled with the selected clusters and the objects written.
+func (c *Controller) distribute(ctx context.Context, obj *unstructured.Unstructured, policy *bootstrappingv1alpha1.BootstrapPolicy,
+	legacy bool, clusterList []clusterv1alpha1.Cluster, status *bootstrappingv1alpha1.ResourceStatus) (ctrl.Result, error) {
+	// the legacy annotations were never validated, keep accepting them as they are.
+	if !legacy {
+		if errs := bootstrappingv1alpha1.ValidateBootstrapPolicy(policy); len(errs) != 0 {
+			klog.Errorf("BootstrapPolicy %s/%s is invalid: %v", policy.Namespace, policy.Name, errs.ToAggregate())
+			status.Error = errs.ToAggregate().Error()
+			return ctrl.Result{}, nil
+		}
+	}
+
+	tolerations := append(append([]corev1.Toleration{}, policy.Spec.Placement.ClusterTolerations...), c.tolerations(obj)...)
+	// the Works of the retained clusters are kept as they are, the evicted ones are pruned.
+	retained, evicted, evictAfter := c.retainedClusters(policy, tolerations, clusterList, time.Now())
+	var clusters []string
+	for _, cluster := range c.skipClusters(obj, policy, tolerations, clusterList) {
+		// the clusters whose NoExecute taint was tolerated for a while are no target anymore.
+		if !evicted.Has(cluster) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	status.Clusters = clusters
+	kept := append(append([]string{}, clusters...), retained...)
+
+	// the artifacts of the other mode are removed once the ones of the policy's mode are applied.
+	applied := false
+	// rolloutAfter is the deadline of the wave of a staged rollout in progress.
+	var rolloutAfter time.Duration
+	mode := policy.Spec.DistributionMode()
+	// the dependencies are written before the Works of the object, its pods need them to start.
+	if mode == bootstrappingv1alpha1.DistributionModeWork {
+		var dependencyClusters []string
+		if policy.Spec.PropagateDeps {
+			dependencyClusters = kept
+		}
+		if err := c.syncDependencies(ctx, obj, dependencyClusters); err != nil {
+			klog.Errorf("Failed to sync the dependencies of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+	switch {
+	case mode == bootstrappingv1alpha1.DistributionModePropagationPolicy && len(kept) == 0:
+		// karmada would keep propagating to the clusters of the PropagationPolicy.
+		if err := c.removePropagationPolicy(ctx, obj); err != nil {
+			klog.Errorf("Failed to remove the PropagationPolicy of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+	case len(clusters) == 0:
+		// nothing to write, every Work is pruned below.
+	case mode == bootstrappingv1alpha1.DistributionModePropagationPolicy:
+		// the retained clusters stay in the policy until they are evicted, karmada reschedules their replicas then.
+		name, err := c.buildPropagationPolicy(ctx, obj, &policy.Spec.Placement, policy.Spec.PropagateDeps, kept)
+		status.PropagationPolicy = name
+		if errors.Is(err, errNotOwned) {
+			// the conflict is reported in the status until the PropagationPolicy is removed.
+			status.Error = err.Error()
+			break
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		applied = true
+	default:
+		var works []bootstrappingv1alpha1.WorkReference
+		var err error
+		if rollout := policy.Spec.Rollout; rollout != nil {
+			works, status.Rollout, rolloutAfter, err = c.rolloutWorks(ctx, obj, rollout, clusters)
+		} else {
+			var revision *workloadRevision
+			if revision, err = c.currentRevision(obj); err == nil {
+				works, err = c.buildWorks(ctx, obj, revision, clusters)
+			}
+		}
+		status.Works = works
+		if err != nil {
+			klog.Errorf("Failed to build work for namespace %q %s %q. Error: %v.", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		status.RolledOut = resourceRolledOut(status)
+		applied = true
+	}
 
-	return reconcile.Result{}, nil
+	// the Works are pruned once the ones of the target clusters are written.
+	if err := c.pruneWorks(ctx, obj, kept, evicted); err != nil {
+		klog.Errorf("Failed to prune the works of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	requeueAfter := shorterRequeue(evictAfter, rolloutAfter)
+	if applied {
+		var retireAfter time.Duration
+		var err error
+		if mode == bootstrappingv1alpha1.DistributionModePropagationPolicy {
+			// karmada propagates the dependencies along with the object once its Works are retired.
+			if retireAfter, err = c.retireWorks(ctx, obj); err == nil && retireAfter == 0 {
+				err = c.syncDependencies(ctx, obj, nil)
+			}
+		} else {
+			retireAfter, err = c.retirePropagationPolicy(ctx, obj, clusters)
+		}
+		if err != nil {
+			klog.Errorf("Failed to switch the distribution mode of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		requeueAfter = shorterRequeue(requeueAfter, retireAfter)
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// shorterRequeue returns the shorter of the requeue delays, zero ones are not set.
+func shorterRequeue(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
 }
 
-func (c *Controller) skipClusters(deployment *appsv1.Deployment, clusters []clusterv1alpha1.Cluster) []string {
+// skipClusters returns the names of the member clusters the policy places the object on.
+// With ClusterHealthAwarePlacement, the clusters which are not ready or carry a
+// NoSchedule or NoExecute taint the tolerations do not tolerate are skipped.
+func (c *Controller) skipClusters(obj client.Object, policy *bootstrappingv1alpha1.BootstrapPolicy, tolerations []corev1.Toleration,
+	clusters []clusterv1alpha1.Cluster) []string {
 	var validClusters []string
 
-	annotations := deployment.GetAnnotations()
-	if v, ok := annotations["bootstrapping.karmada.io/deployments-global"]; ok && v == "true" {
-		klog.Infof("namespace %q deployment %q global distribution.", deployment.Namespace, deployment.Name)
-		for _, cluster := range clusters {
-			validClusters = append(validClusters, cluster.Name)
-		}
-		return validClusters
+	if features.FeatureGate.Enabled(features.ClusterHealthAwarePlacement) {
+		clusters = schedulableClusters(clusters, tolerations)
 	}
 
-	if _, ok := annotations["bootstrapping.karmada.io/deployments-members"]; !ok {
-		klog.Infof("namespace %q deployment %q no global distribution is set, nor distributed to the specified member cluster, skip.", deployment.Namespace, deployment.Name)
+	placed, err := placedClusters(policy, clusters)
+	if err != nil {
+		klog.Errorf("Invalid cluster selector of namespace %q %s %q: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
 		return nil
 	}
+	for _, cluster := range placed {
+		validClusters = append(validClusters, cluster.Name)
+	}
+
+	members := policy.Spec.Placement.ClusterNames
+	if len(members) == 0 && policy.Spec.Placement.ClusterSelector == nil {
+		return validClusters
+	}
+	klog.Infof("member clusters: %v, cluster selector: %v, valid number of valid clusters: %v", members, policy.Spec.Placement.ClusterSelector, validClusters)
+	if len(validClusters) == 0 {
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonSkippedNoTargetClusters,
+			"None of the member clusters %v matching %v exist, skip distribution.", members, metav1.FormatLabelSelector(policy.Spec.Placement.ClusterSelector))
+	}
+	return validClusters
+}
+
+// placedClusters returns the clusters listed in the placement whose labels match its selector.
+func placedClusters(policy *bootstrappingv1alpha1.BootstrapPolicy, clusters []clusterv1alpha1.Cluster) ([]clusterv1alpha1.Cluster, error) {
+	selector := labels.Everything()
+	if policy.Spec.Placement.ClusterSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(policy.Spec.Placement.ClusterSelector)
+		if err != nil {
+			return nil, err
+		}
+		selector = s
+	}
 
-	members := strings.Split(annotations["bootstrapping.karmada.io/deployments-members"], ",")
+	members := sets.NewString(policy.Spec.Placement.ClusterNames...)
+	var placed []clusterv1alpha1.Cluster
 	for _, cluster := range clusters {
-		for _, member := range members {
-			if cluster.Name == member {
-				validClusters = append(validClusters, cluster.Name)
-				continue
-			}
+		if !selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		if members.Len() != 0 && !members.Has(cluster.Name) {
+			continue
 		}
+		placed = append(placed, cluster)
 	}
+	return placed, nil
+}
 
-	klog.Infof("member clusters: %v,valid number of valid clusters: %v", members, validClusters)
-	return validClusters
+// annotationKey returns the annotation key with the configured prefix.
+func (c *Controller) annotationKey(name string) string {
+	return c.config.AnnotationPrefix + "/" + name
 }
 
-func (c *Controller) removeWorks(request ctrl.Request, clusters []clusterv1alpha1.Cluster) error {
-	for _, cluster := range clusters {
-		workNamespace := names.GenerateExecutionSpaceName(cluster.Name)
+// cleanup deletes the Works of the object and releases the finalizer once all
+// of them are gone and the object released its dependencies. A non-zero result
+// means the Works are still being deleted.
+func (c *Controller) cleanup(ctx context.Context, obj *unstructured.Unstructured) (ctrl.Result, error) {
+	pending, err := c.removeWorks(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err != nil {
+		klog.Errorf("delete namespace %q %s %q work failed. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{}, err
+	}
+	if pending > 0 {
+		klog.V(2).Infof("%d works of namespace %q %s %q are still being deleted.", pending, obj.GetNamespace(), c.gvk.Kind, obj.GetName())
+		return ctrl.Result{Requeue: true}, nil
+	}
+	// the dependencies are released once the pods using them are gone.
+	if err := c.releaseDependencies(ctx, c.apiReader, client.ObjectKeyFromObject(obj), nil); err != nil {
+		klog.Errorf("Failed to release the dependencies of namespace %q %s %q. err: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{}, err
+	}
+	if err := c.removeFinalizer(ctx, obj); err != nil {
+		klog.Errorf("Failed to remove the finalizer of namespace %q %s %q. error: %v", obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
 
-		worksList, err := c.dynamicClient.Resource(workGVR).Namespace(workNamespace).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: fmt.Sprintf("bootstrapping.karmada.io/%s", request.Name),
-		})
+// removeWorks deletes the Works of the object from every execution namespace,
+// the ones of the unjoined clusters included, and returns the number of Works
+// still being deleted. The failures of the clusters are aggregated, events are
+// only recorded while the object still exists.
+func (c *Controller) removeWorks(ctx context.Context, key types.NamespacedName, obj *unstructured.Unstructured) (int, error) {
+	var uid types.UID
+	if obj != nil {
+		uid = obj.GetUID()
+	}
+	// the finalizer is released on this answer, the Works are read from the apiserver rather than from the cache.
+	works, err := c.ownedWorks(ctx, c.apiReader, key, uid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list works: %v", err)
+	}
+
+	pending := 0
+	var errs []error
+	for i := range works {
+		work := &works[i]
+		workNamespace := work.Namespace
+		cluster, err := names.GetClusterName(workNamespace)
 		if err != nil {
-			return nil
+			continue
 		}
-
-		if len(worksList.Items) == 0 {
-			return nil
+		if !work.DeletionTimestamp.IsZero() {
+			pending++
+			continue
+		}
+		if c.dryRun {
+			klog.Infof("[dry-run] would delete Work %s/%s of %s %s for cluster %s", workNamespace, work.Name, c.gvk.Kind, key, cluster)
+			continue
 		}
-		for _, work := range worksList.Items {
-			if err := c.dynamicClient.Resource(workGVR).Namespace(workNamespace).Delete(context.TODO(), work.GetName(), metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.Delete(ctx, work); err != nil {
+			if apierrors.IsNotFound(err) {
 				continue
 			}
-			klog.Infof("Delete cluster %q namespace %q deployment %q work successful.", cluster.Name, request.Namespace, request.Name)
+			if obj != nil {
+				c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkDeleteFailed,
+					"Failed to delete Work %s/%s for cluster %s: %v", workNamespace, work.Name, cluster, err)
+			}
+			errs = append(errs, fmt.Errorf("cluster %s: %v", cluster, err))
+			continue
+		}
+		// the execution controller holds the Work until the resource is removed from the member cluster.
+		pending++
+		metrics.RecordWorkOperation(cluster, metrics.OperationDeleted)
+		if obj != nil {
+			c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonWorkDeleted,
+				"Work %s/%s deleted for cluster %s", workNamespace, work.Name, cluster)
 		}
+		klog.Infof("Delete cluster %q namespace %q %s %q work successful.", cluster, key.Namespace, c.gvk.Kind, key.Name)
 	}
 
-	return nil
+	return pending, utilerrors.NewAggregate(errs)
 }
 
-func (c *Controller) buildWorks(deployment *appsv1.Deployment, clusters []string) error {
-	uncastObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
-	if err != nil {
-		klog.Errorf("Failed to transform deployment %s. Error: %v", deployment.GetName(), err)
+// ensureFinalizer adds the finalizer to the object before any Work is written for it.
+func (c *Controller) ensureFinalizer(ctx context.Context, obj *unstructured.Unstructured) error {
+	if controllerutil.ContainsFinalizer(obj, finalizer) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would add finalizer %q to %s %s/%s", finalizer, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
 		return nil
 	}
-	deploymentObj := &unstructured.Unstructured{Object: uncastObj}
+	controllerutil.AddFinalizer(obj, finalizer)
+	return c.Client.Update(ctx, obj)
+}
+
+// removeFinalizer releases the object once its Works are gone.
+func (c *Controller) removeFinalizer(ctx context.Context, obj *unstructured.Unstructured) error {
+	if !controllerutil.ContainsFinalizer(obj, finalizer) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would remove finalizer %q from %s %s/%s", finalizer, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+		return nil
+	}
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	return c.Client.Update(ctx, obj)
+}
+
+// pruneWorks deletes the Works of the object from the clusters outside of
+// kept, unjoined clusters included. Every deletion is reported on its own, the
+// ones of the evicted clusters as evictions.
+func (c *Controller) pruneWorks(ctx context.Context, obj *unstructured.Unstructured, kept []string, evicted sets.String) error {
+	keptNamespaces := sets.NewString()
+	for _, cluster := range kept {
+		keptNamespaces.Insert(names.GenerateExecutionSpaceName(cluster))
+	}
+	works, err := c.ownedWorks(ctx, c.Client, client.ObjectKeyFromObject(obj), obj.GetUID())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range works {
+		work := &works[i]
+		if keptNamespaces.Has(work.Namespace) {
+			continue
+		}
+		cluster, err := names.GetClusterName(work.Namespace)
+		if err != nil {
+			continue
+		}
+		if c.dryRun {
+			klog.Infof("[dry-run] would prune Work %s/%s of %s %s/%s from cluster %s", work.Namespace, work.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName(), cluster)
+			continue
+		}
+		if err := c.Client.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkDeleteFailed,
+				"Failed to prune Work %s/%s from cluster %s: %v", work.Namespace, work.Name, cluster, err)
+			errs = append(errs, fmt.Errorf("cluster %s: %v", cluster, err))
+			continue
+		}
+		metrics.RecordWorkOperation(cluster, metrics.OperationDeleted)
+		if evicted.Has(cluster) {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkEvicted,
+				"Work %s/%s evicted from cluster %s, it is unhealthy or tainted NoExecute", work.Namespace, work.Name, cluster)
+		} else {
+			c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonWorkPruned,
+				"Work %s/%s pruned from cluster %s, it is no longer a target", work.Namespace, work.Name, cluster)
+		}
+		klog.Infof("Prune cluster %q namespace %q %s %q work successful.", cluster, obj.GetNamespace(), c.gvk.Kind, obj.GetName())
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// hasInvalidLegacyPlacement tells whether the deployment carries a cluster selector annotation which cannot be parsed.
+func (c *Controller) hasInvalidLegacyPlacement(obj client.Object) bool {
+	if c.gvk != deploymentGVK {
+		return false
+	}
+	selector, ok := obj.GetAnnotations()[c.annotationKey(annotationClusterSelector)]
+	if !ok {
+		return false
+	}
+	_, err := metav1.ParseToLabelSelector(selector)
+	return err != nil
+}
+
+// buildWorks writes the revision of the workload of the object in a Work per
+// cluster, with the overrides of the cluster applied, and returns the Works
+// written with the state karmada reported for their workloads.
+func (c *Controller) buildWorks(ctx context.Context, obj *unstructured.Unstructured, revision *workloadRevision,
+	clusters []string) ([]bootstrappingv1alpha1.WorkReference, error) {
+	var works []bootstrappingv1alpha1.WorkReference
+	workName := names.GenerateWorkName(c.gvk.Kind, obj.GetName(), obj.GetNamespace())
+	// the ClusterOverridePolicies and OverridePolicies are applied the way karmada applies them to the resources it propagates.
+	overrideManager := overridemanager.New(c.Client, c.recorder)
 
 	for _, cluster := range clusters {
 		workNamespace := names.GenerateExecutionSpaceName(cluster)
+		// the workload may come from the cache, the overrides and the labels of the Work are applied to a copy.
+		resource := revision.workload.DeepCopy()
+		clusterOverrides, overrides, err := overrideManager.ApplyOverridePolicies(resource, cluster)
+		if err != nil {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonOverrideFailed,
+				"Failed to apply the overrides of cluster %s: %v", cluster, err)
+			return works, fmt.Errorf("cluster %s: %v", cluster, err)
+		}
 
-		workName := names.GenerateWorkName(deploymentObj.GetKind(), deploymentObj.GetName(), deploymentObj.GetNamespace())
 		objectMeta := metav1.ObjectMeta{
-			Name:       workName,
-			Namespace:  workNamespace,
-			Finalizers: []string{karmadautil.ExecutionControllerFinalizer},
-			/*                      OwnerReferences: []metav1.OwnerReference{
-			        *metav1.NewControllerRef(deployment, deployment.GroupVersionKind()),
-			},*/
-			Labels: map[string]string{fmt.Sprintf("bootstrapping.karmada.io/%s", deployment.Name): "true"},
+			Name:        workName,
+			Namespace:   workNamespace,
+			Finalizers:  []string{karmadautil.ExecutionControllerFinalizer},
+			Labels:      ownerLabels(client.ObjectKeyFromObject(obj)),
+			Annotations: map[string]string{annotationOwnerUID: string(obj.GetUID()), annotationRevision: revision.name},
+		}
+		if err := recordAppliedOverrides(objectMeta.Annotations, clusterOverrides, overrides); err != nil {
+			return works, err
+		}
+		klog.Infof("BuildWorks: WorkNamespace %q WorkName %q %s %s/%s", objectMeta.Namespace, objectMeta.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+		karmadautil.MergeLabel(resource, workv1alpha1.WorkNamespaceLabel, workNamespace)
+		karmadautil.MergeLabel(resource, workv1alpha1.WorkNameLabel, workName)
+		if c.dryRun {
+			if err := c.dryRunWork(ctx, objectMeta, resource); err != nil {
+				return works, err
+			}
+			continue
 		}
-		klog.Infof("BuildWorks: WorkNamespace %q WorkName %q DeploymentNamespace %q DeploymentName %q", objectMeta.Namespace, objectMeta.Name, deployment.Namespace, deployment.Name)
-		karmadautil.MergeLabel(deploymentObj, workv1alpha1.WorkNamespaceLabel, workNamespace)
-		karmadautil.MergeLabel(deploymentObj, workv1alpha1.WorkNameLabel, workName)
-		if err = helper.CreateOrUpdateWork(c.Client, objectMeta, deploymentObj); err != nil {
-			return err
+		work, result, err := c.createOrUpdateWork(ctx, cluster, objectMeta, resource)
+		if errors.Is(err, errNotOwned) {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkConflict,
+				"Work %s/%s for cluster %s exists and was not written for this %s", workNamespace, workName, cluster, c.gvk.Kind)
+			return works, err
+		}
+		if err != nil {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonWorkSyncFailed,
+				"Failed to sync Work %s/%s for cluster %s: %v", workNamespace, workName, cluster, err)
+			return works, err
+		}
+		works = append(works, workReference(cluster, work))
+		if err := c.removeLegacyWork(ctx, workNamespace, obj); err != nil {
+			return works, err
+		}
+		switch result {
+		case controllerutil.OperationResultCreated:
+			metrics.RecordWorkOperation(cluster, metrics.OperationCreated)
+			c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonWorkCreated,
+				"Work %s/%s created for cluster %s", workNamespace, workName, cluster)
+		case controllerutil.OperationResultUpdated:
+			metrics.RecordWorkOperation(cluster, metrics.OperationUpdated)
+			c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonWorkUpdated,
+				"Work %s/%s updated for cluster %s", workNamespace, workName, cluster)
 		}
 	}
-	return nil
+	return works, nil
+}
+
+// propagationPolicyName returns the name of the PropagationPolicy of the object.
+// Deployments keep the bare name they always had, the other kinds are suffixed
+// with the kind so that objects of the same name do not share a policy.
+func (c *Controller) propagationPolicyName(name string) string {
+	if c.gvk == deploymentGVK {
+		return name
+	}
+	return strings.ToLower(name + "-" + c.gvk.Kind)
 }
 
-// buildPropagationPolicy create PropagationPolicy
-func (c *Controller) buildPropagationPolicy(deployment *appsv1.Deployment, clusters []string) {
+// buildPropagationPolicy applies the PropagationPolicy of the object, it returns the name of the PropagationPolicy.
+// A PropagationPolicy with the same name which is not controlled by the object is left untouched.
+// With propagateDeps, karmada propagates the dependencies of the object along with it.
+func (c *Controller) buildPropagationPolicy(ctx context.Context, obj *unstructured.Unstructured, placement *bootstrappingv1alpha1.Placement,
+	propagateDeps bool, clusters []string) (string, error) {
 	pp := &policy1alpha1.PropagationPolicy{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: policy1alpha1.GroupVersion.String(),
 			Kind:       "PropagationPolicy",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      deployment.Name,
-			Namespace: deployment.Namespace,
+			Name:      c.propagationPolicyName(obj.GetName()),
+			Namespace: obj.GetNamespace(),
 			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(deployment, deployment.GroupVersionKind()),
+				*metav1.NewControllerRef(obj, c.gvk),
 			},
 		},
 		Spec: policy1alpha1.PropagationSpec{
 			ResourceSelectors: []policy1alpha1.ResourceSelector{
 				{
-					APIVersion: deployment.APIVersion,
-					Kind:       "Deployment",
-					Name:       deployment.Name,
-					Namespace:  deployment.Namespace,
-				},
-			},
-			Placement: policy1alpha1.Placement{
-				ClusterAffinity: &policy1alpha1.ClusterAffinity{
-					ClusterNames: clusters,
-				},
-				ReplicaScheduling: &policy1alpha1.ReplicaSchedulingStrategy{
-					ReplicaDivisionPreference: policy1alpha1.ReplicaDivisionPreferenceWeighted,
-					ReplicaSchedulingType:     policy1alpha1.ReplicaSchedulingTypeDivided,
-					WeightPreference: &policy1alpha1.ClusterPreferences{
-						StaticWeightList: []policy1alpha1.StaticClusterWeight{
-							{
-								TargetCluster: policy1alpha1.ClusterAffinity{
-									ClusterNames: clusters,
-								},
-								Weight: 1,
-							},
-						},
-					},
+					APIVersion: c.gvk.GroupVersion().String(),
+					Kind:       c.gvk.Kind,
+					Name:       obj.GetName(),
+					Namespace:  obj.GetNamespace(),
 				},
 			},
+			Placement:     propagationPlacement(placement, clusters),
+			PropagateDeps: propagateDeps,
 		},
 	}
 
-	result, err := controllerutil.CreateOrUpdate(context.TODO(), c.Client, pp, func() error { return nil })
+	var existing client.Object
+	current := &policy1alpha1.PropagationPolicy{}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(pp), current); err == nil {
+		if !metav1.IsControlledBy(current, obj) {
+			err := fmt.Errorf("PropagationPolicy %s/%s exists and is not controlled by %s %s/%s: %w",
+				pp.Namespace, pp.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName(), errNotOwned)
+			klog.Errorf("Refuse to apply PropagationPolicy %s. err: %v", pp.GetName(), err)
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonPropagationPolicyConflict,
+				"PropagationPolicy %s exists and is not controlled by this %s", pp.GetName(), c.gvk.Kind)
+			return pp.GetName(), err
+		}
+		existing = current
+	} else if !apierrors.IsNotFound(err) {
+		return pp.GetName(), err
+	}
+
+	if c.dryRun {
+		if err := c.dryRunPropagationPolicy(ctx, pp); err != nil {
+			klog.Errorf("Failed to dry-run PropagationPolicy %s. err: %v", pp.GetName(), err)
+			return pp.GetName(), err
+		}
+		return pp.GetName(), nil
+	}
+
+	ctx, span := tracing.Start(ctx, "deployment.ApplyPropagationPolicy",
+		tracing.AttributeNamespace.String(pp.Namespace), tracing.AttributeName.String(pp.Name))
+	result, err := c.apply(ctx, pp, existing)
+	tracing.End(span, err)
 	if err != nil {
 		klog.Errorf("Failed transform PropagationPolicy %s. err: %v", pp.GetName(), err)
-		return
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonPropagationPolicyApplyFailed,
+			"Failed to apply PropagationPolicy %s: %v", pp.GetName(), err)
+		return pp.GetName(), err
 	}
 	if result == controllerutil.OperationResultCreated {
+		metrics.RecordPropagationPolicyOperation(metrics.OperationCreated)
 		klog.Infof("Namespace %q Create PropagationPolicy %q successfully.", pp.GetNamespace(), pp.GetName())
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonPropagationPolicyApplied,
+			"PropagationPolicy %s created for clusters %v", pp.GetName(), clusters)
 	} else if result == controllerutil.OperationResultUpdated {
+		metrics.RecordPropagationPolicyOperation(metrics.OperationUpdated)
 		klog.Infof("Namespace %q Update PropagationPolicy %q successfully.", pp.GetNamespace(), pp.GetName())
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonPropagationPolicyApplied,
+			"PropagationPolicy %s updated for clusters %v", pp.GetName(), clusters)
 	} else {
 		klog.V(3).Infof("Namespace %q Update PropagationPolicy %q is up to date.", pp.GetNamespace(), pp.GetName())
 	}
+	return pp.GetName(), nil
 }
 
 func (c *Controller) SetupWithManager(mgr manager.Manager) error {
+	// status-only updates of a policy are written by this controller, they need no reconcile.
+	policyPredicate := predicate.GenerationChangedPredicate{}
+	// a joined cluster is a new target, the Works of a removed one are cleaned up,
+	// and only the label, taint and readiness changes of a cluster can change the target clusters.
+	clusterPredicate := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*clusterv1alpha1.Cluster)
+			if !ok {
+				return false
+			}
+			newCluster, ok := e.ObjectNew.(*clusterv1alpha1.Cluster)
+			if !ok {
+				return false
+			}
+			return clusterSchedulingChanged(oldCluster, newCluster)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+	// the overrides of the clusters only change with the spec of the override policies.
+	overridePolicyPredicate := predicate.GenerationChangedPredicate{}
+	// the status of the Works is aggregated on the policies, the Works themselves are written by this controller.
+	workPredicate := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldWork, ok := e.ObjectOld.(*workv1alpha1.Work)
+			if !ok {
+				return false
+			}
+			curWork, ok := e.ObjectNew.(*workv1alpha1.Work)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldWork.Status, curWork.Status)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
 	predicate := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			return true
@@ -268,29 +767,77 @@ func (c *Controller) SetupWithManager(mgr manager.Manager) error {
 	       // Uncomment the following line adding a pointer to an instance of the controlled resource as an argument
 	       // For().
 	       Complete(r)*/
-	return ctrl.NewControllerManagedBy(mgr).For(&appsv1.Deployment{}).WithEventFilter(predicate).Complete(c)
+	b := ctrl.NewControllerManagedBy(mgr).Named(controllerName(c.gvk)).For(c.newObject()).
+		Watches(&source.Kind{Type: &bootstrappingv1alpha1.BootstrapPolicy{}}, handler.EnqueueRequestsFromMapFunc(c.policyToObjects),
+			builder.WithPredicates(policyPredicate)).
+		Watches(&source.Kind{Type: &clusterv1alpha1.Cluster{}}, handler.EnqueueRequestsFromMapFunc(c.clusterToObjects),
+			builder.WithPredicates(clusterPredicate)).
+		Watches(&source.Kind{Type: &workv1alpha1.Work{}}, handler.EnqueueRequestsFromMapFunc(c.workToObject),
+			builder.WithPredicates(workPredicate)).
+		Watches(&source.Kind{Type: &policy1alpha1.OverridePolicy{}}, handler.EnqueueRequestsFromMapFunc(c.overridePolicyToObjects),
+			builder.WithPredicates(overridePolicyPredicate)).
+		Watches(&source.Kind{Type: &policy1alpha1.ClusterOverridePolicy{}}, handler.EnqueueRequestsFromMapFunc(c.overridePolicyToObjects),
+			builder.WithPredicates(overridePolicyPredicate))
+	// the dependencies are read from the apiserver when they are propagated, only their metadata is cached.
+	for _, kind := range dependencyKinds {
+		dep := &metav1.PartialObjectMetadata{}
+		dep.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+		b = b.Watches(&source.Kind{Type: dep}, handler.EnqueueRequestsFromMapFunc(c.dependencyToObjects(kind)))
+	}
+	return b.WithEventFilter(predicate).Complete(c)
 }
 
-// NewController returns a new Controller
-func NewController(mgr manager.Manager, dynamicClient dynamic.Interface) *Controller {
-	return &Controller{
-		Client:        mgr.GetClient(),
-		scheme:        mgr.GetScheme(),
-		recorder:      mgr.GetEventRecorderFor(ControllerName),
-		dynamicClient: dynamicClient,
+// controllerName returns the name of the controller of the kind, unique among the configured kinds.
+func controllerName(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return strings.ToLower(gvk.Kind)
 	}
+	return strings.ToLower(gvk.Kind + "." + gvk.Group)
 }
 
-// AddToManager create controller and register to controller manager
-func AddToManager(mgr manager.Manager) error {
-	// Setup Scheme for k8s appv1 resources
-	if err := appsv1.AddToScheme(mgr.GetScheme()); err != nil {
-		return err
+// newObject returns an empty object of the kind of the controller.
+func (c *Controller) newObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(c.gvk)
+	return obj
+}
+
+// newList returns an empty list of the kind of the controller.
+func (c *Controller) newList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(c.gvk.GroupVersion().WithKind(c.gvk.Kind + "List"))
+	return list
+}
+
+// NewController returns a new Controller of the kind, in dry-run mode it writes nothing, events included.
+func NewController(mgr manager.Manager, config configv1alpha1.DeploymentControllerConfiguration,
+	gvk schema.GroupVersionKind, dryRun bool) *Controller {
+	var recorder record.EventRecorder = &record.FakeRecorder{}
+	if !dryRun {
+		recorder = mgr.GetEventRecorderFor(ControllerName)
+	}
+
+	return &Controller{
+		Client:    mgr.GetClient(),
+		scheme:    mgr.GetScheme(),
+		gvk:       gvk,
+		recorder:  recorder,
+		apiReader: mgr.GetAPIReader(),
+		config:    config,
+		dryRun:    dryRun,
 	}
+}
+
+// AddToManager creates a controller per configured kind and registers them to controller manager
+func AddToManager(mgr manager.Manager, config configv1alpha1.DeploymentControllerConfiguration, dryRun bool) error {
 	// Setup Scheme for karmada clusterv1alpha1 resources
 	if err := clusterv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
 	}
+	// the revisions of a staged rollout are recorded in ControllerRevisions.
+	if err := appsv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
 	if err := policy1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
 	}
@@ -298,11 +845,19 @@ func AddToManager(mgr manager.Manager) error {
 	if err := workv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
 	}
-
-	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
-	if err != nil {
+	if err := workv1alpha2.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := bootstrappingv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
 	}
 
-	return NewController(mgr, dynamicClient).SetupWithManager(mgr)
+	for _, resource := range config.Resources {
+		gvk := schema.GroupVersionKind(resource)
+		if err := NewController(mgr, config, gvk, dryRun).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed to setup the controller of %s: %v", gvk, err)
+		}
+		klog.Infof("Distributing %s through BootstrapPolicies.", gvk)
+	}
+	return nil
 }
@@ -0,0 +1,580 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+)
+
+var nginx = types.NamespacedName{Namespace: "default", Name: "nginx"}
+
+// newTestController returns a deployment controller reading and writing the objects through a fake client.
+func newTestController(objs ...client.Object) *Controller {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(policy1alpha1.AddToScheme(scheme))
+	utilruntime.Must(workv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(workv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(bootstrappingv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Controller{
+		Client:    c,
+		scheme:    scheme,
+		recorder:  record.NewFakeRecorder(100),
+		apiReader: c,
+		config:    configv1alpha1.DeploymentControllerConfiguration{AnnotationPrefix: configv1alpha1.DefaultDeploymentAnnotationPrefix},
+		gvk:       deploymentGVK,
+	}
+}
+
+// failingClient fails the deletions in the namespaces of failDelete, every list
+// with listErr and every server-side apply with applyErr.
+type failingClient struct {
+	client.Client
+	failDelete sets.String
+	listErr    error
+	applyErr   error
+}
+
+func (f *failingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if f.failDelete.Has(obj.GetNamespace()) {
+		return errors.New("delete refused")
+	}
+	return f.Client.Delete(ctx, obj, opts...)
+}
+
+func (f *failingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if f.listErr != nil {
+		return f.listErr
+	}
+	return f.Client.List(ctx, list, opts...)
+}
+
+func (f *failingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if f.applyErr != nil && patch.Type() == types.ApplyPatchType {
+		return f.applyErr
+	}
+	return f.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// applyClient emulates the server-side apply of Works the fake client lacks,
+// an apply which changes nothing writes nothing. The writes are recorded.
+type applyClient struct {
+	client.Client
+	writes []string
+}
+
+func (a *applyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	a.writes = append(a.writes, fmt.Sprintf("create %T %s", obj, obj.GetName()))
+	return a.Client.Create(ctx, obj, opts...)
+}
+
+func (a *applyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	a.writes = append(a.writes, fmt.Sprintf("update %T %s", obj, obj.GetName()))
+	return a.Client.Update(ctx, obj, opts...)
+}
+
+func (a *applyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	a.writes = append(a.writes, fmt.Sprintf("delete %T %s", obj, obj.GetName()))
+	return a.Client.Delete(ctx, obj, opts...)
+}
+
+func (a *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	work, ok := obj.(*workv1alpha1.Work)
+	if !ok || patch.Type() != types.ApplyPatchType {
+		a.writes = append(a.writes, fmt.Sprintf("patch %T %s", obj, obj.GetName()))
+		return a.Client.Patch(ctx, obj, patch, opts...)
+	}
+	current := &workv1alpha1.Work{}
+	if err := a.Client.Get(ctx, client.ObjectKeyFromObject(work), current); apierrors.IsNotFound(err) {
+		return a.Create(ctx, work)
+	} else if err != nil {
+		return err
+	}
+	// the manifests are compared as the apiserver stores them.
+	currentSpec, _ := json.Marshal(current.Spec)
+	spec, _ := json.Marshal(work.Spec)
+	if reflect.DeepEqual(current.Labels, work.Labels) && reflect.DeepEqual(current.Annotations, work.Annotations) &&
+		string(currentSpec) == string(spec) {
+		current.DeepCopyInto(work)
+		return nil
+	}
+	work.ResourceVersion = current.ResourceVersion
+	return a.Update(ctx, work)
+}
+
+func testDeployment(annotations map[string]string, finalizers ...string) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Namespace: nginx.Namespace, Name: nginx.Name, UID: "uid", Annotations: annotations, Finalizers: finalizers,
+	}}
+}
+
+// ownedWork returns the Work of the nginx deployment for the cluster.
+func ownedWork(cluster string) *workv1alpha1.Work {
+	return &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{
+		Namespace: names.GenerateExecutionSpaceName(cluster),
+		Name:      names.GenerateWorkName(deploymentGVK.Kind, nginx.Name, nginx.Namespace),
+		Labels:    ownerLabels(nginx),
+	}}
+}
+
+// remainingWorks returns the clusters of the Works of the nginx deployment.
+func remainingWorks(t *testing.T, c *Controller) sets.String {
+	t.Helper()
+	works, err := c.ownedWorks(context.TODO(), c.Client, nginx, "")
+	if err != nil {
+		t.Fatalf("ownedWorks() error = %v", err)
+	}
+	clusters := sets.NewString()
+	for _, work := range works {
+		cluster, _ := names.GetClusterName(work.Namespace)
+		clusters.Insert(cluster)
+	}
+	return clusters
+}
+
+// recordedReasons returns the reasons of the events recorded so far.
+func recordedReasons(c *Controller) []string {
+	recorder := c.recorder.(*record.FakeRecorder)
+	var reasons []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			reasons = append(reasons, strings.Fields(event)[1])
+		default:
+			return reasons
+		}
+	}
+}
+
+func TestPruneWorks(t *testing.T) {
+	clusters := []string{"member1", "member2", "member3", "member4"}
+	tests := []struct {
+		name        string
+		kept        []string
+		evicted     sets.String
+		failDelete  []string
+		wantWorks   []string
+		wantReasons []string
+		wantErrs    []string
+	}{
+		{
+			name:        "target and retained clusters",
+			kept:        []string{"member1", "member2"},
+			evicted:     sets.NewString("member4"),
+			wantWorks:   []string{"member1", "member2"},
+			wantReasons: []string{"WorkPruned", "WorkEvicted"},
+		},
+		{
+			name:        "no target",
+			wantWorks:   []string{},
+			wantReasons: []string{"WorkPruned", "WorkPruned", "WorkPruned", "WorkPruned"},
+		},
+		{
+			name:        "failures of every cluster",
+			kept:        []string{"member1"},
+			failDelete:  []string{"member2", "member4"},
+			wantWorks:   []string{"member1", "member2", "member4"},
+			wantReasons: []string{"WorkDeleteFailed", "WorkPruned", "WorkDeleteFailed"},
+			wantErrs:    []string{"cluster member2", "cluster member4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			for _, cluster := range clusters {
+				objs = append(objs, ownedWork(cluster))
+			}
+			c := newTestController(objs...)
+			failDelete := sets.NewString()
+			for _, cluster := range tt.failDelete {
+				failDelete.Insert(names.GenerateExecutionSpaceName(cluster))
+			}
+			c.Client = &failingClient{Client: c.Client, failDelete: failDelete}
+
+			obj := c.newObject()
+			obj.SetNamespace(nginx.Namespace)
+			obj.SetName(nginx.Name)
+			err := c.pruneWorks(context.TODO(), obj, tt.kept, tt.evicted)
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("pruneWorks() error = %v", err)
+			}
+			for _, want := range tt.wantErrs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("pruneWorks() error = %v, want it to mention %q", err, want)
+				}
+			}
+			if got := remainingWorks(t, c); !got.Equal(sets.NewString(tt.wantWorks...)) {
+				t.Errorf("remaining works = %v, want %v", got.List(), tt.wantWorks)
+			}
+			if got := recordedReasons(c); strings.Join(got, ",") != strings.Join(tt.wantReasons, ",") {
+				t.Errorf("events = %v, want %v", got, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestReconcilePrunesWorksOfUndistributedDeployment(t *testing.T) {
+	c := newTestController(testDeployment(nil), ownedWork("member1"), ownedWork("member2"))
+	if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := remainingWorks(t, c); got.Len() != 0 {
+		t.Errorf("remaining works = %v, want none once the global annotation is removed", got.List())
+	}
+}
+
+func TestRemoveWorks(t *testing.T) {
+	outsider := ownedWork("member1")
+	outsider.Namespace = "default"
+	c := newTestController(ownedWork("member1"), ownedWork("member2"), ownedWork("member3"), outsider)
+	c.Client = &failingClient{Client: c.Client, failDelete: sets.NewString(
+		names.GenerateExecutionSpaceName("member1"), names.GenerateExecutionSpaceName("member3"))}
+
+	pending, err := c.removeWorks(context.TODO(), nginx, nil)
+	for _, want := range []string{"cluster member1", "cluster member3"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("removeWorks() error = %v, want it to mention %q", err, want)
+		}
+	}
+	if pending != 1 {
+		t.Errorf("removeWorks() pending = %d, want 1", pending)
+	}
+	if got := remainingWorks(t, c); !got.Equal(sets.NewString("member1", "member3", "")) {
+		t.Errorf("remaining works = %v, want the failed clusters and the work outside of the execution namespaces", got.List())
+	}
+
+	c.apiReader = &failingClient{Client: c.apiReader.(client.Client), listErr: errors.New("list refused")}
+	if _, err := c.removeWorks(context.TODO(), nginx, nil); err == nil || !strings.Contains(err.Error(), "list refused") {
+		t.Errorf("removeWorks() error = %v, want the list failure", err)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	work := ownedWork("member1")
+	work.Finalizers = []string{"karmada.io/execution-controller"}
+	c := newTestController(testDeployment(nil, finalizer), work)
+	get := func() *unstructured.Unstructured {
+		obj := c.newObject()
+		if err := c.Client.Get(context.TODO(), nginx, obj); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return obj
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := c.cleanup(context.TODO(), get())
+		if err != nil || !result.Requeue {
+			t.Fatalf("cleanup() = %v, %v, want a requeue while the work is being deleted", result, err)
+		}
+		if !controllerutil.ContainsFinalizer(get(), finalizer) {
+			t.Fatalf("finalizer removed while the work is being deleted")
+		}
+	}
+
+	// the execution controller releases the Work once the member cluster is cleaned up.
+	if err := c.Client.Get(context.TODO(), client.ObjectKeyFromObject(work), work); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	work.Finalizers = nil
+	if err := c.Client.Update(context.TODO(), work); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if result, err := c.cleanup(context.TODO(), get()); err != nil || result.Requeue {
+		t.Fatalf("cleanup() = %v, %v, want done", result, err)
+	}
+	if controllerutil.ContainsFinalizer(get(), finalizer) {
+		t.Errorf("finalizer kept once the works are gone")
+	}
+}
+
+func TestEnsureFinalizer(t *testing.T) {
+	c := newTestController(testDeployment(nil))
+	obj := c.newObject()
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	c.dryRun = true
+	if err := c.ensureFinalizer(context.TODO(), obj); err != nil || controllerutil.ContainsFinalizer(obj, finalizer) {
+		t.Fatalf("ensureFinalizer() in dry-run = %v, finalizers %v", err, obj.GetFinalizers())
+	}
+	c.dryRun = false
+	if err := c.ensureFinalizer(context.TODO(), obj); err != nil {
+		t.Fatalf("ensureFinalizer() error = %v", err)
+	}
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil || !controllerutil.ContainsFinalizer(obj, finalizer) {
+		t.Fatalf("finalizers = %v, %v, want %q", obj.GetFinalizers(), err, finalizer)
+	}
+	if err := c.removeFinalizer(context.TODO(), obj); err != nil {
+		t.Fatalf("removeFinalizer() error = %v", err)
+	}
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil || controllerutil.ContainsFinalizer(obj, finalizer) {
+		t.Errorf("finalizers = %v, %v, want none", obj.GetFinalizers(), err)
+	}
+}
+
+// staleClient lists the policies as they were before the other writers updated them.
+type staleClient struct {
+	client.Client
+	policies *bootstrappingv1alpha1.BootstrapPolicyList
+}
+
+func (s *staleClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if policies, ok := list.(*bootstrappingv1alpha1.BootstrapPolicyList); ok {
+		s.policies.DeepCopyInto(policies)
+		return nil
+	}
+	return s.Client.List(ctx, list, opts...)
+}
+
+func TestSyncPolicyStatusConflict(t *testing.T) {
+	policy := &bootstrappingv1alpha1.BootstrapPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: nginx.Namespace, Name: "policy"}}
+	c := newTestController(policy)
+	stale := &bootstrappingv1alpha1.BootstrapPolicyList{}
+	if err := c.Client.List(context.TODO(), stale); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	// the status of another deployment of the policy is written meanwhile.
+	other := bootstrappingv1alpha1.ResourceStatus{APIVersion: "apps/v1", Kind: "Deployment", Name: "apache"}
+	if err := c.syncPolicyStatus(context.TODO(), types.NamespacedName{Namespace: nginx.Namespace, Name: other.Name}, policy, &other); err != nil {
+		t.Fatalf("syncPolicyStatus() error = %v", err)
+	}
+
+	c.Client = &staleClient{Client: c.Client, policies: stale}
+	status := bootstrappingv1alpha1.ResourceStatus{APIVersion: "apps/v1", Kind: "Deployment", Name: nginx.Name}
+	if err := c.syncPolicyStatus(context.TODO(), nginx, policy, &status); err != nil {
+		t.Fatalf("syncPolicyStatus() error = %v", err)
+	}
+	if err := c.apiReader.Get(context.TODO(), client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	var got []string
+	for _, resource := range policy.Status.Resources {
+		got = append(got, resource.Name)
+	}
+	if strings.Join(got, ",") != "apache,nginx" {
+		t.Errorf("resources = %v, want both the one written meanwhile and the new one", got)
+	}
+}
+
+func TestSyncLegacyStatus(t *testing.T) {
+	c := newTestController(testDeployment(map[string]string{"other": "value"}))
+	key := c.annotationKey(annotationStatus)
+	obj := c.newObject()
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	status := &bootstrappingv1alpha1.ResourceStatus{APIVersion: "apps/v1", Kind: "Deployment", Name: nginx.Name, Clusters: []string{"member1"},
+		Works: []bootstrappingv1alpha1.WorkReference{{Cluster: "member1", Applied: metav1.ConditionTrue}}}
+	if err := c.syncLegacyStatus(context.TODO(), obj, status); err != nil {
+		t.Fatalf("syncLegacyStatus() error = %v", err)
+	}
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got := &bootstrappingv1alpha1.ResourceStatus{}
+	if err := json.Unmarshal([]byte(obj.GetAnnotations()[key]), got); err != nil || !reflect.DeepEqual(got, status) {
+		t.Errorf("status annotation = %q, %v, want %+v", obj.GetAnnotations()[key], err, status)
+	}
+
+	if err := c.syncLegacyStatus(context.TODO(), obj, nil); err != nil {
+		t.Fatalf("syncLegacyStatus() error = %v", err)
+	}
+	if err := c.Client.Get(context.TODO(), nginx, obj); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if annotations := obj.GetAnnotations(); !reflect.DeepEqual(annotations, map[string]string{"other": "value"}) {
+		t.Errorf("annotations = %v, want the status annotation removed", annotations)
+	}
+}
+
+func TestReconcileWritesLegacyStatusOnce(t *testing.T) {
+	deployment := testDeployment(map[string]string{
+		"bootstrapping.karmada.io/deployments-members": "member1",
+	})
+	c := newTestController(deployment, &clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}})
+	applier := &applyClient{Client: c.Client}
+	c.Client = applier
+
+	if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(applier.writes) == 0 {
+		t.Fatalf("the first Reconcile() wrote nothing")
+	}
+	applier.writes = nil
+	if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(applier.writes) != 0 {
+		t.Errorf("the second Reconcile() wrote %v, want nothing", applier.writes)
+	}
+}
+
+func TestRecordPreviousRevisions(t *testing.T) {
+	c := newTestController()
+	obj := c.newObject()
+	obj.SetNamespace(nginx.Namespace)
+	obj.SetName(nginx.Name)
+	obj.SetUID("uid")
+
+	works := map[string]*workv1alpha1.Work{}
+	for cluster, annotations := range map[string]map[string]string{
+		"member1": {annotationRevision: "plain"},
+		"member2": {annotationRevision: "overridden", karmadautil.AppliedOverrides: `[{"policyName":"images"}]`},
+	} {
+		work := ownedWork(cluster)
+		work.Annotations = annotations
+		work.Spec.Workload.Manifests = []workv1alpha1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)}}}
+		works[cluster] = work
+	}
+
+	revisions := map[string]*appsv1.ControllerRevision{}
+	if err := c.recordPreviousRevisions(context.TODO(), obj, "desired", []string{"member1", "member2"}, works, revisions); err != nil {
+		t.Fatalf("recordPreviousRevisions() error = %v", err)
+	}
+	if revisions[c.controllerRevisionName(obj, "plain")] == nil {
+		t.Errorf("revision of the Work without overrides not recorded")
+	}
+	if revisions[c.controllerRevisionName(obj, "overridden")] != nil {
+		t.Errorf("revision recorded from the workload the overrides of its cluster were applied to")
+	}
+}
+
+// propagationObjects returns the PropagationPolicy of the nginx deployment and its ResourceBinding.
+func propagationObjects(controlled bool) (*policy1alpha1.PropagationPolicy, *workv1alpha2.ResourceBinding) {
+	pp := &policy1alpha1.PropagationPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: nginx.Namespace, Name: nginx.Name}}
+	if controlled {
+		pp.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(testDeployment(nil), deploymentGVK)}
+	}
+	binding := &workv1alpha2.ResourceBinding{ObjectMeta: metav1.ObjectMeta{
+		Namespace: nginx.Namespace, Name: names.GenerateBindingName(deploymentGVK.Kind, nginx.Name)}}
+	return pp, binding
+}
+
+func TestReconcileRemovesPropagationPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		controlled  bool
+		wantRemoved bool
+	}{
+		{
+			name:        "no longer distributed",
+			controlled:  true,
+			wantRemoved: true,
+		},
+		{
+			name: "no target cluster",
+			annotations: map[string]string{
+				"bootstrapping.karmada.io/deployments-members": "member1",
+				"bootstrapping.karmada.io/deployments-force":   "false",
+			},
+			controlled:  true,
+			wantRemoved: true,
+		},
+		{
+			name: "not controlled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp, binding := propagationObjects(tt.controlled)
+			c := newTestController(testDeployment(tt.annotations), pp, binding)
+			if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			for _, obj := range []client.Object{pp, binding} {
+				err := c.Client.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+				if removed := apierrors.IsNotFound(err); removed != tt.wantRemoved {
+					t.Errorf("%T removed = %v, want %v, err: %v", obj, removed, tt.wantRemoved, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileRetriesClusterListFailure(t *testing.T) {
+	c := newTestController(testDeployment(nil, finalizer))
+	c.Client = &failingClient{Client: c.Client, listErr: errors.New("list refused")}
+	if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err == nil {
+		t.Errorf("Reconcile() error = nil, want the list failure to be retried")
+	}
+}
+
+func TestReconcilePropagationPolicyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		applyErr error
+		wantErr  bool
+	}{
+		{
+			name: "not owned",
+		},
+		{
+			name:     "apply failed",
+			applyErr: errors.New("apply refused"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &bootstrappingv1alpha1.BootstrapPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: nginx.Namespace, Name: "policy"},
+				Spec: bootstrappingv1alpha1.BootstrapPolicySpec{
+					ResourceSelector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment"},
+					Placement:        bootstrappingv1alpha1.Placement{ClusterNames: []string{"member1"}},
+					Mode:             bootstrappingv1alpha1.DistributionModePropagationPolicy,
+				},
+			}
+			objs := []client.Object{testDeployment(nil), policy, &clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}}}
+			if tt.applyErr == nil {
+				pp, _ := propagationObjects(false)
+				objs = append(objs, pp)
+			}
+			c := newTestController(objs...)
+			c.Client = &failingClient{Client: c.Client, applyErr: tt.applyErr}
+
+			_, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := c.apiReader.Get(context.TODO(), client.ObjectKeyFromObject(policy), policy); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if len(policy.Status.Resources) != 1 || policy.Status.Resources[0].Error == "" {
+				t.Errorf("resources = %+v, want the error in the status", policy.Status.Resources)
+			}
+		})
+	}
+}
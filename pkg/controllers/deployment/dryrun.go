@@ -0,0 +1,82 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+)
+
+// dryRunWork logs the difference between the Work in the karmada control plane and the desired one.
+func (c *Controller) dryRunWork(ctx context.Context, workMeta metav1.ObjectMeta, resource *unstructured.Unstructured) error {
+	desired, err := newWork(workMeta, resource)
+	if err != nil {
+		return err
+	}
+	desiredManifests, err := decodeManifests(desired)
+	if err != nil {
+		return err
+	}
+
+	existing := &workv1alpha1.Work{}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("[dry-run] would create Work %s/%s:\n%s", desired.Namespace, desired.Name, cmp.Diff(nil, desiredManifests))
+			return nil
+		}
+		return err
+	}
+	existingManifests, err := decodeManifests(existing)
+	if err != nil {
+		return err
+	}
+
+	diff := cmp.Diff(existing.Labels, desired.Labels) + cmp.Diff(existingManifests, desiredManifests)
+	if diff == "" {
+		klog.Infof("[dry-run] Work %s/%s is up to date", desired.Namespace, desired.Name)
+		return nil
+	}
+	klog.Infof("[dry-run] would update Work %s/%s (-current +desired):\n%s", desired.Namespace, desired.Name, diff)
+	return nil
+}
+
+// dryRunPropagationPolicy logs the difference between the PropagationPolicy in the karmada control plane and the desired one.
+func (c *Controller) dryRunPropagationPolicy(ctx context.Context, desired *policy1alpha1.PropagationPolicy) error {
+	existing := &policy1alpha1.PropagationPolicy{}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("[dry-run] would create PropagationPolicy %s/%s:\n%s", desired.Namespace, desired.Name, cmp.Diff(nil, desired.Spec))
+			return nil
+		}
+		return err
+	}
+
+	diff := cmp.Diff(existing.Spec, desired.Spec)
+	if diff == "" {
+		klog.Infof("[dry-run] PropagationPolicy %s/%s is up to date", desired.Namespace, desired.Name)
+		return nil
+	}
+	klog.Infof("[dry-run] would update PropagationPolicy %s/%s (-current +desired):\n%s", desired.Namespace, desired.Name, diff)
+	return nil
+}
+
+// decodeManifests decodes the raw manifests of the Work, so that they can be compared field by field.
+func decodeManifests(work *workv1alpha1.Work) ([]map[string]interface{}, error) {
+	manifests := make([]map[string]interface{}, 0, len(work.Spec.Workload.Manifests))
+	for _, manifest := range work.Spec.Workload.Manifests {
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(manifest.Raw, &obj); err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, obj)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,202 @@
+package deployment
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+)
+
+// tolerations returns the cluster tolerations declared on the object, none when
+// the annotation cannot be decoded.
+func (c *Controller) tolerations(obj client.Object) []corev1.Toleration {
+	value, ok := obj.GetAnnotations()[c.annotationKey(annotationClusterTolerations)]
+	if !ok {
+		return nil
+	}
+
+	var tolerations []corev1.Toleration
+	if err := json.Unmarshal([]byte(value), &tolerations); err != nil {
+		klog.Errorf("Invalid cluster tolerations %q of namespace %q %s %q: %v", value, obj.GetNamespace(), c.gvk.Kind, obj.GetName(), err)
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonInvalidClusterTolerations,
+			"Invalid cluster tolerations %q: %v", value, err)
+		return nil
+	}
+	return tolerations
+}
+
+// schedulableClusters filters out the clusters which are not ready, or carry a
+// NoSchedule or NoExecute taint the tolerations do not tolerate.
+func schedulableClusters(clusters []clusterv1alpha1.Cluster, tolerations []corev1.Toleration) []clusterv1alpha1.Cluster {
+	var schedulable []clusterv1alpha1.Cluster
+	for i := range clusters {
+		if _, notReady := notReadySince(&clusters[i]); notReady {
+			klog.V(2).Infof("cluster %q is not ready, skip.", clusters[i].Name)
+			continue
+		}
+		if effect := untoleratedTaintEffect(&clusters[i], tolerations); effect != "" {
+			klog.V(2).Infof("cluster %q has an untolerated %s taint, skip.", clusters[i].Name, effect)
+			continue
+		}
+		schedulable = append(schedulable, clusters[i])
+	}
+	return schedulable
+}
+
+// retainedClusters returns the clusters the policy places the object on which
+// are skipped but whose Works are kept: the ready ones tainted NoSchedule, and
+// the ones tainted NoExecute or NotReady for less than the eviction grace
+// period. The clusters whose NoExecute taint is tolerated for a while are
+// evicted once the toleration seconds are over. Nothing is evicted when the
+// grace period is zero, evictAfter is the time left before the next eviction.
+func (c *Controller) retainedClusters(policy *bootstrappingv1alpha1.BootstrapPolicy, tolerations []corev1.Toleration,
+	clusters []clusterv1alpha1.Cluster, now time.Time) (retained []string, evicted sets.String, evictAfter time.Duration) {
+	evicted = sets.NewString()
+	if !features.FeatureGate.Enabled(features.ClusterHealthAwarePlacement) {
+		return nil, evicted, 0
+	}
+	placed, err := placedClusters(policy, clusters)
+	if err != nil {
+		return nil, evicted, 0
+	}
+
+	grace := c.config.EvictionGracePeriod.Duration
+	for i := range placed {
+		cluster := &placed[i]
+		if at, ok := evictionTime(cluster, tolerations, grace); ok {
+			left := at.Sub(now)
+			if left <= 0 {
+				klog.Infof("cluster %q is not ready or tainted NoExecute past the eviction grace period %v or the toleration seconds.", cluster.Name, grace)
+				evicted.Insert(cluster.Name)
+				continue
+			}
+			evictAfter = shorterRequeue(evictAfter, left)
+		}
+
+		_, notReady := notReadySince(cluster)
+		if notReady || untoleratedTaintEffect(cluster, tolerations) != "" {
+			retained = append(retained, cluster.Name)
+		}
+	}
+	return retained, evicted, evictAfter
+}
+
+// evictionTime returns when the Works of the cluster are evicted: the grace
+// period after it became NotReady or after an untolerated NoExecute taint was
+// added, the toleration seconds after a tolerated one was added, whichever
+// comes first. ok is false when they are never evicted.
+func evictionTime(cluster *clusterv1alpha1.Cluster, tolerations []corev1.Toleration, grace time.Duration) (at time.Time, ok bool) {
+	if grace <= 0 {
+		return time.Time{}, false
+	}
+	earlier := func(t time.Time) {
+		if !ok || t.Before(at) {
+			at, ok = t, true
+		}
+	}
+
+	if since, notReady := notReadySince(cluster); notReady {
+		earlier(since.Add(grace))
+	}
+	for i := range cluster.Spec.Taints {
+		taint := &cluster.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		// the taints added before their time was recorded are as old as the cluster.
+		added := cluster.CreationTimestamp.Time
+		if taint.TimeAdded != nil {
+			added = taint.TimeAdded.Time
+		}
+		tolerated, seconds := tolerationSeconds(taint, tolerations)
+		switch {
+		case !tolerated:
+			earlier(added.Add(grace))
+		case seconds != nil:
+			earlier(added.Add(time.Duration(*seconds) * time.Second))
+		}
+	}
+	return at, ok
+}
+
+// tolerationSeconds returns whether the tolerations tolerate the taint, and the
+// shortest of the toleration seconds of the matching ones, nil when all of them
+// tolerate it forever.
+func tolerationSeconds(taint *corev1.Taint, tolerations []corev1.Toleration) (bool, *int64) {
+	tolerated := false
+	var seconds *int64
+	for i := range tolerations {
+		if !tolerations[i].ToleratesTaint(taint) {
+			continue
+		}
+		tolerated = true
+		if s := tolerations[i].TolerationSeconds; s != nil && (seconds == nil || *s < *seconds) {
+			seconds = s
+		}
+	}
+	return tolerated, seconds
+}
+
+// untoleratedTaintEffect returns the strongest effect of the NoSchedule and
+// NoExecute taints of the cluster the tolerations do not tolerate, empty when
+// all of them are tolerated.
+func untoleratedTaintEffect(cluster *clusterv1alpha1.Cluster, tolerations []corev1.Toleration) corev1.TaintEffect {
+	var effect corev1.TaintEffect
+	for i := range cluster.Spec.Taints {
+		taint := &cluster.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if tolerated {
+			continue
+		}
+		if taint.Effect == corev1.TaintEffectNoExecute {
+			return corev1.TaintEffectNoExecute
+		}
+		effect = corev1.TaintEffectNoSchedule
+	}
+	return effect
+}
+
+// notReadySince returns whether the cluster is not ready and since when, the
+// creation of the cluster when it never reported its readiness.
+func notReadySince(cluster *clusterv1alpha1.Cluster) (time.Time, bool) {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, clusterv1alpha1.ClusterConditionReady)
+	if condition == nil {
+		return cluster.CreationTimestamp.Time, true
+	}
+	if condition.Status == "True" {
+		return time.Time{}, false
+	}
+	return condition.LastTransitionTime.Time, true
+}
+
+// clusterSchedulingChanged tells whether the update of the cluster may change
+// the objects placed on it: its labels, taints or readiness.
+func clusterSchedulingChanged(oldCluster, newCluster *clusterv1alpha1.Cluster) bool {
+	if !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+		!equality.Semantic.DeepEqual(oldCluster.Spec.Taints, newCluster.Spec.Taints) {
+		return true
+	}
+	_, oldNotReady := notReadySince(oldCluster)
+	_, newNotReady := notReadySince(newCluster)
+	return oldNotReady != newNotReady
+}
@@ -0,0 +1,129 @@
+package deployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+)
+
+var readyStatus = clusterv1alpha1.ClusterStatus{Conditions: []metav1.Condition{
+	{Type: clusterv1alpha1.ClusterConditionReady, Status: metav1.ConditionTrue},
+}}
+
+func notReadyStatus(since time.Time) clusterv1alpha1.ClusterStatus {
+	return clusterv1alpha1.ClusterStatus{Conditions: []metav1.Condition{
+		{Type: clusterv1alpha1.ClusterConditionReady, Status: metav1.ConditionFalse, LastTransitionTime: metav1.NewTime(since)},
+	}}
+}
+
+// enableClusterHealthAwarePlacement turns on the feature gate for the test.
+func enableClusterHealthAwarePlacement(t *testing.T) {
+	t.Helper()
+	gate := string(features.ClusterHealthAwarePlacement)
+	if err := features.FeatureGate.SetFromMap(map[string]bool{gate: true}); err != nil {
+		t.Fatalf("failed to enable %s: %v", gate, err)
+	}
+	t.Cleanup(func() {
+		_ = features.FeatureGate.SetFromMap(map[string]bool{gate: false})
+	})
+}
+
+func TestClusterHealth(t *testing.T) {
+	enableClusterHealthAwarePlacement(t)
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	infra := corev1.Taint{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}
+	drain := corev1.Taint{Key: "drain", Effect: corev1.TaintEffectNoExecute, TimeAdded: &metav1.Time{Time: now.Add(-time.Hour)}}
+	maintenance := corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoExecute, TimeAdded: &metav1.Time{Time: now.Add(-2 * time.Minute)}}
+	clusters := []clusterv1alpha1.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ready"}, Status: readyStatus},
+		{ObjectMeta: metav1.ObjectMeta{Name: "infra"}, Spec: clusterv1alpha1.ClusterSpec{Taints: []corev1.Taint{infra}}, Status: readyStatus},
+		{ObjectMeta: metav1.ObjectMeta{Name: "drained"}, Spec: clusterv1alpha1.ClusterSpec{Taints: []corev1.Taint{drain}}, Status: readyStatus},
+		{ObjectMeta: metav1.ObjectMeta{Name: "maintained"}, Spec: clusterv1alpha1.ClusterSpec{Taints: []corev1.Taint{maintenance}}, Status: readyStatus},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lost-recently"}, Status: notReadyStatus(now.Add(-time.Minute))},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lost-long-ago"}, Status: notReadyStatus(now.Add(-time.Hour))},
+	}
+	tolerateFor := func(seconds int64) []corev1.Toleration {
+		return []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "infra"},
+			{Key: "drain", Operator: corev1.TolerationOpExists},
+			{Key: "maintenance", Operator: corev1.TolerationOpExists, TolerationSeconds: &seconds},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		tolerations   []corev1.Toleration
+		gracePeriod   time.Duration
+		wantTargets   []string
+		wantRetained  []string
+		wantEvicted   []string
+		wantRequeueIn time.Duration
+	}{
+		{
+			name:         "no eviction",
+			wantTargets:  []string{"ready"},
+			wantRetained: []string{"infra", "drained", "maintained", "lost-recently", "lost-long-ago"},
+		},
+		{
+			name:          "grace period",
+			gracePeriod:   10 * time.Minute,
+			wantTargets:   []string{"ready"},
+			wantRetained:  []string{"infra", "maintained", "lost-recently"},
+			wantEvicted:   []string{"drained", "lost-long-ago"},
+			wantRequeueIn: 8 * time.Minute,
+		},
+		{
+			name:          "tolerations",
+			tolerations:   tolerateFor(300),
+			gracePeriod:   10 * time.Minute,
+			wantTargets:   []string{"ready", "infra", "drained", "maintained"},
+			wantRetained:  []string{"lost-recently"},
+			wantEvicted:   []string{"lost-long-ago"},
+			wantRequeueIn: 3 * time.Minute,
+		},
+		{
+			name:          "expired toleration",
+			tolerations:   tolerateFor(60),
+			gracePeriod:   10 * time.Minute,
+			wantTargets:   []string{"ready", "infra", "drained", "maintained"},
+			wantRetained:  []string{"lost-recently"},
+			wantEvicted:   []string{"maintained", "lost-long-ago"},
+			wantRequeueIn: 9 * time.Minute,
+		},
+	}
+	policy := &bootstrappingv1alpha1.BootstrapPolicy{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{config: configv1alpha1.DeploymentControllerConfiguration{EvictionGracePeriod: metav1.Duration{Duration: tt.gracePeriod}}}
+
+			var targets []string
+			for _, cluster := range schedulableClusters(clusters, tt.tolerations) {
+				targets = append(targets, cluster.Name)
+			}
+			if !reflect.DeepEqual(targets, tt.wantTargets) {
+				t.Errorf("schedulableClusters() = %v, want %v", targets, tt.wantTargets)
+			}
+
+			retained, evicted, requeueIn := c.retainedClusters(policy, tt.tolerations, clusters, now)
+			if !reflect.DeepEqual(retained, tt.wantRetained) {
+				t.Errorf("retained = %v, want %v", retained, tt.wantRetained)
+			}
+			if got := evicted.List(); !reflect.DeepEqual(got, sets.NewString(tt.wantEvicted...).List()) {
+				t.Errorf("evicted = %v, want %v", got, tt.wantEvicted)
+			}
+			if requeueIn != tt.wantRequeueIn {
+				t.Errorf("evictAfter = %v, want %v", requeueIn, tt.wantRequeueIn)
+			}
+		})
+	}
+}
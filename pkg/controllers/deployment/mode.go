@@ -0,0 +1,205 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+)
+
+// The Works written in Work mode and the ones karmada derives from the
+// ResourceBinding of a PropagationPolicy have the same name, so a mode switch
+// hands the Works over in place. The artifacts of the previous mode are only
+// removed once the ones of the new mode are applied, the workloads of the
+// member clusters never disappear in between.
+
+// modeSwitchRetryInterval is how often a switch to PropagationPolicy mode checks
+// whether karmada applied the ResourceBinding.
+const modeSwitchRetryInterval = 10 * time.Second
+
+// bindingKey returns the key of the ResourceBinding karmada derives for the object from its PropagationPolicy.
+func (c *Controller) bindingKey(obj client.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: names.GenerateBindingName(c.gvk.Kind, obj.GetName())}
+}
+
+// isBindingWork tells whether karmada derived the Work from the ResourceBinding.
+func isBindingWork(work *workv1alpha1.Work, binding types.NamespacedName) bool {
+	return work.Labels[workv1alpha2.ResourceBindingReferenceKey] == names.GenerateBindingReferenceKey(binding.Namespace, binding.Name)
+}
+
+// releaseBindingWork removes the references to the ResourceBinding from a Work
+// karmada derived from it, so that the deletion of the binding leaves the Work
+// to the Work mode.
+func (c *Controller) releaseBindingWork(ctx context.Context, work *workv1alpha1.Work) error {
+	patch := client.MergeFrom(work.DeepCopy())
+	delete(work.Labels, workv1alpha2.ResourceBindingReferenceKey)
+	delete(work.Annotations, workv1alpha2.ResourceBindingNamespaceAnnotationKey)
+	delete(work.Annotations, workv1alpha2.ResourceBindingNameAnnotationKey)
+	if err := c.Client.Patch(ctx, work, patch); err != nil {
+		return err
+	}
+	klog.Infof("Release work %s/%s from its ResourceBinding.", work.Namespace, work.Name)
+	return nil
+}
+
+// retireWorks removes the Works written in Work mode once karmada fully applied
+// the ResourceBinding of the PropagationPolicy. By then karmada rewrote the
+// Works with the same name for the binding, and the workloads of the target
+// clusters are labeled with the Works of the binding, the deletion of the
+// remaining ones leaves them in place. requeueAfter is set while the binding
+// is not applied.
+func (c *Controller) retireWorks(ctx context.Context, obj *unstructured.Unstructured) (requeueAfter time.Duration, err error) {
+	works, err := c.ownedWorks(ctx, c.Client, client.ObjectKeyFromObject(obj), obj.GetUID())
+	if err != nil || len(works) == 0 {
+		return 0, err
+	}
+
+	binding := &workv1alpha2.ResourceBinding{}
+	if err := c.apiReader.Get(ctx, c.bindingKey(obj), binding); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		klog.V(2).Infof("ResourceBinding of %s %s/%s does not exist yet, keep its works.", c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+		return modeSwitchRetryInterval, nil
+	}
+	if !meta.IsStatusConditionTrue(binding.Status.Conditions, workv1alpha2.FullyApplied) {
+		klog.V(2).Infof("ResourceBinding %s/%s is not fully applied yet, keep the works.", binding.Namespace, binding.Name)
+		return modeSwitchRetryInterval, nil
+	}
+
+	// the cache may not have seen karmada rewrite the Works yet.
+	works, err = c.ownedWorks(ctx, c.apiReader, client.ObjectKeyFromObject(obj), obj.GetUID())
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for i := range works {
+		work := &works[i]
+		cluster, err := names.GetClusterName(work.Namespace)
+		if err != nil {
+			continue
+		}
+		if c.dryRun {
+			klog.Infof("[dry-run] would remove Work %s/%s of %s %s/%s, the PropagationPolicy took over", work.Namespace, work.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+			continue
+		}
+		// the preconditions fail when karmada rewrote the Work in the meantime.
+		preconditions := client.Preconditions{UID: &work.UID, ResourceVersion: &work.ResourceVersion}
+		if err := c.Client.Delete(ctx, work, preconditions); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", cluster, err))
+			continue
+		}
+		metrics.RecordWorkOperation(cluster, metrics.OperationDeleted)
+	}
+	if len(errs) != 0 {
+		return 0, utilerrors.NewAggregate(errs)
+	}
+	if !c.dryRun {
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonModeSwitched,
+			"Switched to %s mode, the Works of ResourceBinding %s took over", bootstrappingv1alpha1.DistributionModePropagationPolicy, binding.Name)
+	}
+	return 0, nil
+}
+
+// retirePropagationPolicy removes the PropagationPolicy written in
+// PropagationPolicy mode and its ResourceBinding once the Works of the target
+// clusters are applied. The Works they hold were released from the binding, the
+// binding still holds the Works of the other clusters only and they are
+// removed with it. requeueAfter is set while karmada still holds a Work of a
+// target cluster.
+func (c *Controller) retirePropagationPolicy(ctx context.Context, obj *unstructured.Unstructured, clusters []string) (requeueAfter time.Duration, err error) {
+	pp := &policy1alpha1.PropagationPolicy{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: c.propagationPolicyName(obj.GetName())}, pp); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pp, obj) {
+		return 0, nil
+	}
+	bindingKey := c.bindingKey(obj)
+	if c.dryRun {
+		klog.Infof("[dry-run] would remove PropagationPolicy %s/%s and ResourceBinding %s, the Works took over", pp.Namespace, pp.Name, bindingKey)
+		return 0, nil
+	}
+
+	works := &workv1alpha1.WorkList{}
+	if err := c.apiReader.List(ctx, works, client.MatchingLabels{
+		workv1alpha2.ResourceBindingReferenceKey: names.GenerateBindingReferenceKey(bindingKey.Namespace, bindingKey.Name),
+	}); err != nil {
+		return 0, err
+	}
+	targets := sets.NewString()
+	for _, cluster := range clusters {
+		targets.Insert(names.GenerateExecutionSpaceName(cluster))
+	}
+	for i := range works.Items {
+		if targets.Has(works.Items[i].Namespace) {
+			// karmada rewrote the Work after it was released, it is released again on the next sync.
+			klog.V(2).Infof("Work %s/%s is still held by ResourceBinding %s, keep it.", works.Items[i].Namespace, works.Items[i].Name, bindingKey)
+			return modeSwitchRetryInterval, nil
+		}
+	}
+
+	if err := c.deletePropagationPolicy(ctx, obj, pp); err != nil {
+		return 0, err
+	}
+	c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonModeSwitched,
+		"Switched to %s mode, PropagationPolicy %s removed", bootstrappingv1alpha1.DistributionModeWork, pp.Name)
+	return 0, nil
+}
+
+// removePropagationPolicy removes the PropagationPolicy the object controls and
+// its ResourceBinding, when the object has no target cluster left or is no
+// longer distributed. karmada removes the workloads of the Works of the binding.
+func (c *Controller) removePropagationPolicy(ctx context.Context, obj *unstructured.Unstructured) error {
+	pp := &policy1alpha1.PropagationPolicy{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: c.propagationPolicyName(obj.GetName())}, pp); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(pp, obj) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would remove PropagationPolicy %s/%s and ResourceBinding %s", pp.Namespace, pp.Name, c.bindingKey(obj))
+		return nil
+	}
+	if err := c.deletePropagationPolicy(ctx, obj, pp); err != nil {
+		return err
+	}
+	c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonPropagationPolicyRemoved,
+		"PropagationPolicy %s removed, no member cluster is targeted", pp.Name)
+	return nil
+}
+
+// deletePropagationPolicy deletes the PropagationPolicy of the object and its ResourceBinding.
+func (c *Controller) deletePropagationPolicy(ctx context.Context, obj *unstructured.Unstructured, pp *policy1alpha1.PropagationPolicy) error {
+	if err := c.Client.Delete(ctx, pp); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	metrics.RecordPropagationPolicyOperation(metrics.OperationDeleted)
+	bindingKey := c.bindingKey(obj)
+	binding := &workv1alpha2.ResourceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: bindingKey.Namespace, Name: bindingKey.Name}}
+	if err := c.Client.Delete(ctx, binding); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	klog.Infof("Delete PropagationPolicy %s/%s and ResourceBinding %s of %s %s/%s successful.", pp.Namespace, pp.Name, bindingKey, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+	return nil
+}
@@ -0,0 +1,195 @@
+package deployment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	"github.com/karmada-io/karmada/pkg/util/names"
+)
+
+func TestIsBindingWork(t *testing.T) {
+	c := &Controller{gvk: deploymentGVK}
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("default")
+	obj.SetName("nginx")
+	binding := c.bindingKey(obj)
+	if binding.Name != "nginx-deployment" {
+		t.Errorf("binding name = %q, want %q", binding.Name, "nginx-deployment")
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{
+			name:   "derived from the binding",
+			labels: map[string]string{workv1alpha2.ResourceBindingReferenceKey: names.GenerateBindingReferenceKey("default", "nginx-deployment")},
+			want:   true,
+		},
+		{
+			name:   "derived from another binding",
+			labels: map[string]string{workv1alpha2.ResourceBindingReferenceKey: names.GenerateBindingReferenceKey("default", "redis-deployment")},
+		},
+		{
+			name:   "written by the controller",
+			labels: ownerLabels(binding),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			if got := isBindingWork(work, binding); got != tt.want {
+				t.Errorf("isBindingWork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nginxObject returns the nginx deployment as the controller reads it.
+func nginxObject(c *Controller) *unstructured.Unstructured {
+	obj := c.newObject()
+	obj.SetNamespace(nginx.Namespace)
+	obj.SetName(nginx.Name)
+	obj.SetUID(testDeployment(nil).UID)
+	return obj
+}
+
+func TestRetireWorks(t *testing.T) {
+	fullyApplied := []metav1.Condition{{Type: workv1alpha2.FullyApplied, Status: metav1.ConditionTrue}}
+	tests := []struct {
+		name             string
+		conditions       []metav1.Condition
+		noBinding        bool
+		dryRun           bool
+		wantRequeueAfter time.Duration
+		wantWorks        []string
+		wantReasons      []string
+	}{
+		{
+			name:             "binding missing",
+			noBinding:        true,
+			wantRequeueAfter: modeSwitchRetryInterval,
+			wantWorks:        []string{"member1"},
+		},
+		{
+			name:             "binding not fully applied",
+			wantRequeueAfter: modeSwitchRetryInterval,
+			wantWorks:        []string{"member1"},
+		},
+		{
+			name:        "binding fully applied",
+			conditions:  fullyApplied,
+			wantReasons: []string{"ModeSwitched"},
+		},
+		{
+			name:       "dry-run",
+			conditions: fullyApplied,
+			dryRun:     true,
+			wantWorks:  []string{"member1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{ownedWork("member1")}
+			if !tt.noBinding {
+				_, binding := propagationObjects(true)
+				binding.Status.Conditions = tt.conditions
+				objs = append(objs, binding)
+			}
+			c := newTestController(objs...)
+			c.dryRun = tt.dryRun
+
+			requeueAfter, err := c.retireWorks(context.TODO(), nginxObject(c))
+			if err != nil {
+				t.Fatalf("retireWorks() error = %v", err)
+			}
+			if requeueAfter != tt.wantRequeueAfter {
+				t.Errorf("requeueAfter = %v, want %v", requeueAfter, tt.wantRequeueAfter)
+			}
+			if got := remainingWorks(t, c); !got.Equal(sets.NewString(tt.wantWorks...)) {
+				t.Errorf("remaining works = %v, want %v", got.List(), tt.wantWorks)
+			}
+			if got := recordedReasons(c); !reflect.DeepEqual(got, tt.wantReasons) {
+				t.Errorf("events = %v, want %v", got, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestRetirePropagationPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		controlled       bool
+		heldWork         string
+		dryRun           bool
+		wantRequeueAfter time.Duration
+		wantRemoved      bool
+	}{
+		{
+			name:        "no work held",
+			controlled:  true,
+			wantRemoved: true,
+		},
+		{
+			name:        "work of another cluster held",
+			controlled:  true,
+			heldWork:    "member2",
+			wantRemoved: true,
+		},
+		{
+			name:             "work of a target cluster held",
+			controlled:       true,
+			heldWork:         "member1",
+			wantRequeueAfter: modeSwitchRetryInterval,
+		},
+		{
+			name:       "dry-run",
+			controlled: true,
+			dryRun:     true,
+		},
+		{
+			name: "not controlled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp, binding := propagationObjects(tt.controlled)
+			objs := []client.Object{pp, binding}
+			if tt.heldWork != "" {
+				work := ownedWork(tt.heldWork)
+				work.Labels = map[string]string{
+					workv1alpha2.ResourceBindingReferenceKey: names.GenerateBindingReferenceKey(binding.Namespace, binding.Name),
+				}
+				objs = append(objs, work)
+			}
+			c := newTestController(objs...)
+			c.dryRun = tt.dryRun
+
+			requeueAfter, err := c.retirePropagationPolicy(context.TODO(), nginxObject(c), []string{"member1"})
+			if err != nil {
+				t.Fatalf("retirePropagationPolicy() error = %v", err)
+			}
+			if requeueAfter != tt.wantRequeueAfter {
+				t.Errorf("requeueAfter = %v, want %v", requeueAfter, tt.wantRequeueAfter)
+			}
+			for _, obj := range []client.Object{pp, binding} {
+				err := c.Client.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+				if removed := apierrors.IsNotFound(err); removed != tt.wantRemoved {
+					t.Errorf("%T removed = %v, want %v, err: %v", obj, removed, tt.wantRemoved, err)
+				}
+			}
+		})
+	}
+}
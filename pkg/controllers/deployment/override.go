@@ -0,0 +1,58 @@
+package deployment
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/overridemanager"
+)
+
+// In Work mode the ClusterOverridePolicies and OverridePolicies selecting an
+// object are applied to its workload for every cluster by the override manager
+// of karmada, and recorded on the Work the way karmada records them. In
+// PropagationPolicy mode karmada applies them itself.
+
+// recordAppliedOverrides records the applied ClusterOverridePolicies and
+// OverridePolicies in the annotations of a Work, none is recorded when empty.
+func recordAppliedOverrides(annotations map[string]string, clusterOverrides, overrides *overridemanager.AppliedOverrides) error {
+	for annotation, applied := range map[string]*overridemanager.AppliedOverrides{
+		karmadautil.AppliedClusterOverrides: clusterOverrides,
+		karmadautil.AppliedOverrides:        overrides,
+	} {
+		if applied == nil {
+			continue
+		}
+		data, err := applied.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if data != nil {
+			annotations[annotation] = string(data)
+		}
+	}
+	return nil
+}
+
+// overridePolicyToObjects maps an OverridePolicy to the distributed objects of
+// its namespace, and a ClusterOverridePolicy to the ones of every namespace. The
+// objects the policy selected before it changed are reconciled as well.
+func (c *Controller) overridePolicyToObjects(obj client.Object) []reconcile.Request {
+	list := c.newList()
+	if err := c.Client.List(context.TODO(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Errorf("Failed to list %s of namespace %q. error: %v", c.gvk.Kind, obj.GetNamespace(), err)
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		// only the distributed objects carry the finalizer.
+		if controllerutil.ContainsFinalizer(&list.Items[i], finalizer) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+		}
+	}
+	return requests
+}
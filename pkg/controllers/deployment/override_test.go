@@ -0,0 +1,84 @@
+package deployment
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+)
+
+func TestReconcileAppliesOverrides(t *testing.T) {
+	deployment := testDeployment(map[string]string{
+		"bootstrapping.karmada.io/deployments-members": "member1,member2",
+	})
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "nginx", Image: "nginx:1.23"}}
+	// the image is upgraded in member1 only.
+	override := &policy1alpha1.OverridePolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nginx.Namespace, Name: "upgrade"},
+		Spec: policy1alpha1.OverrideSpec{
+			ResourceSelectors: []policy1alpha1.ResourceSelector{{APIVersion: "apps/v1", Kind: "Deployment", Name: nginx.Name}},
+			OverrideRules: []policy1alpha1.RuleWithCluster{{
+				TargetCluster: &policy1alpha1.ClusterAffinity{ClusterNames: []string{"member1"}},
+				Overriders: policy1alpha1.Overriders{ImageOverrider: []policy1alpha1.ImageOverrider{{
+					Component: policy1alpha1.Tag, Operator: policy1alpha1.OverriderOpReplace, Value: "1.24",
+				}}},
+			}},
+		},
+	}
+	apache := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: nginx.Namespace, Name: "apache"}}
+	c := newTestController(deployment, apache, override,
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}},
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member2"}})
+	c.Client = &applyClient{Client: c.Client}
+
+	if _, err := c.Reconcile(context.TODO(), ctrl.Request{NamespacedName: nginx}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for cluster, want := range map[string]struct {
+		image     string
+		overrides bool
+	}{
+		"member1": {image: "nginx:1.24", overrides: true},
+		"member2": {image: "nginx:1.23"},
+	} {
+		work := &workv1alpha1.Work{}
+		key := client.ObjectKey{Namespace: names.GenerateExecutionSpaceName(cluster), Name: names.GenerateWorkName(deploymentGVK.Kind, nginx.Name, nginx.Namespace)}
+		if err := c.Client.Get(context.TODO(), key, work); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		workload, err := workWorkload(work)
+		if err != nil {
+			t.Fatalf("workWorkload() error = %v", err)
+		}
+		containers, _, _ := unstructured.NestedSlice(workload.Object, "spec", "template", "spec", "containers")
+		if len(containers) != 1 || containers[0].(map[string]interface{})["image"] != want.image {
+			t.Errorf("containers of cluster %s = %v, want image %s", cluster, containers, want.image)
+		}
+		applied, ok := work.Annotations[karmadautil.AppliedOverrides]
+		if ok != want.overrides || (ok && !strings.Contains(applied, `"policyName":"upgrade"`)) {
+			t.Errorf("applied overrides of cluster %s = %q, want recorded %v", cluster, applied, want.overrides)
+		}
+		if _, ok := work.Annotations[karmadautil.AppliedClusterOverrides]; ok {
+			t.Errorf("applied cluster overrides of cluster %s recorded, want none", cluster)
+		}
+	}
+
+	// only the distributed deployment is reconciled when the policy changes.
+	want := []ctrl.Request{{NamespacedName: nginx}}
+	if got := c.overridePolicyToObjects(override); !reflect.DeepEqual(got, want) {
+		t.Errorf("overridePolicyToObjects() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+)
+
+const (
+	// labelOwnerNamespace and labelOwnerName identify the object a Work was written for,
+	// their values are hashed when they are not valid label values.
+	// They live under their own prefix, the legacy label of a deployment named after them would collide otherwise.
+	labelOwnerNamespace = "owner.bootstrapping.karmada.io/namespace"
+	labelOwnerName      = "owner.bootstrapping.karmada.io/name"
+	// annotationOwnerUID is the UID of the object a Work was written for.
+	annotationOwnerUID = "bootstrapping.karmada.io/uid"
+	// legacyOwnerLabelPrefix prefixes the name of the deployment in the label of the
+	// Works written before the ownership labels, e.g. "bootstrapping.karmada.io/nginx: true".
+	legacyOwnerLabelPrefix = "bootstrapping.karmada.io/"
+)
+
+// ownerLabels returns the labels identifying the Works of the object.
+func ownerLabels(key types.NamespacedName) map[string]string {
+	return map[string]string{
+		labelOwnerNamespace: labelValue(key.Namespace),
+		labelOwnerName:      labelValue(key.Name),
+	}
+}
+
+// labelValue returns value when it is a valid label value, a truncated value
+// suffixed with its hash otherwise.
+func labelValue(value string) string {
+	if len(utilvalidation.IsValidLabelValue(value)) == 0 {
+		return value
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(value))
+	suffix := fmt.Sprintf("%x", hash.Sum64())
+	prefix := value
+	if max := utilvalidation.LabelValueMaxLength - len(suffix) - 1; len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	return strings.TrimRight(prefix, "-_.") + "-" + suffix
+}
+
+// legacyOwnerLabel returns the label of the Works written before the ownership
+// labels, empty when the name does not fit in a label key.
+func legacyOwnerLabel(name string) string {
+	key := legacyOwnerLabelPrefix + name
+	if len(utilvalidation.IsQualifiedName(key)) != 0 {
+		return ""
+	}
+	return key
+}
+
+// isOwnedWork tells whether the Work was written for the object. The UID is
+// only compared when both of them are known.
+func isOwnedWork(work *workv1alpha1.Work, key types.NamespacedName, uid types.UID) bool {
+	owned := true
+	for k, v := range ownerLabels(key) {
+		if work.Labels[k] != v {
+			owned = false
+		}
+	}
+	if !owned {
+		// the Works written before the ownership labels carry no UID either.
+		legacy := legacyOwnerLabel(key.Name)
+		return legacy != "" && work.Labels[legacy] == "true"
+	}
+	owner := work.Annotations[annotationOwnerUID]
+	return uid == "" || owner == "" || owner == string(uid)
+}
+
+// ownedWorks lists the Works the controller wrote for the object, in every
+// execution namespace. uid is empty when the object is gone.
+func (c *Controller) ownedWorks(ctx context.Context, reader client.Reader, key types.NamespacedName, uid types.UID) ([]workv1alpha1.Work, error) {
+	selectors := []client.ListOption{client.MatchingLabels(ownerLabels(key))}
+	if legacy := legacyOwnerLabel(key.Name); legacy != "" {
+		selectors = append(selectors, client.HasLabels{legacy})
+	}
+
+	workNames := c.workNames(key)
+	seen := sets.NewString()
+	var owned []workv1alpha1.Work
+	for _, selector := range selectors {
+		works := &workv1alpha1.WorkList{}
+		if err := reader.List(ctx, works, selector); err != nil {
+			return nil, err
+		}
+		for i := range works.Items {
+			work := &works.Items[i]
+			// the names hold the kind, the Works of the objects of the other kinds are skipped.
+			if !workNames.Has(work.Name) || seen.Has(work.Namespace+"/"+work.Name) || !isOwnedWork(work, key, uid) {
+				continue
+			}
+			seen.Insert(work.Namespace + "/" + work.Name)
+			owned = append(owned, *work)
+		}
+	}
+	return owned, nil
+}
@@ -0,0 +1,90 @@
+package deployment
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+)
+
+func TestLabelValue(t *testing.T) {
+	long := strings.Repeat("a", 60) + "." + strings.Repeat("b", 60)
+	if got := labelValue("nginx"); got != "nginx" {
+		t.Errorf("labelValue(nginx) = %q, want it unchanged", got)
+	}
+	got := labelValue(long)
+	if errs := utilvalidation.IsValidLabelValue(got); len(errs) != 0 {
+		t.Errorf("labelValue(%q) = %q is invalid: %v", long, got, errs)
+	}
+	if other := labelValue(long + "c"); other == got {
+		t.Errorf("labelValue() = %q for two different names", got)
+	}
+}
+
+func TestIsOwnedWork(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "nginx"}
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		uid         types.UID
+		want        bool
+	}{
+		{
+			name:        "owned",
+			labels:      ownerLabels(key),
+			annotations: map[string]string{annotationOwnerUID: "1"},
+			uid:         "1",
+			want:        true,
+		},
+		{
+			name:   "owner gone",
+			labels: ownerLabels(key),
+			want:   true,
+		},
+		{
+			name:        "other uid",
+			labels:      ownerLabels(key),
+			annotations: map[string]string{annotationOwnerUID: "2"},
+			uid:         "1",
+		},
+		{
+			name:   "other namespace",
+			labels: ownerLabels(types.NamespacedName{Namespace: "kube-system", Name: "nginx"}),
+			uid:    "1",
+		},
+		{
+			name:   "legacy label",
+			labels: map[string]string{"bootstrapping.karmada.io/nginx": "true"},
+			uid:    "1",
+			want:   true,
+		},
+		{
+			name: "foreign",
+			uid:  "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels, Annotations: tt.annotations}}
+			if got := isOwnedWork(work, key, tt.uid); got != tt.want {
+				t.Errorf("isOwnedWork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerLabelsAreNotLegacyLabels(t *testing.T) {
+	work := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Labels: ownerLabels(types.NamespacedName{Namespace: "true", Name: "true"})}}
+	for _, name := range []string{"name", "namespace"} {
+		if isOwnedWork(work, types.NamespacedName{Namespace: "default", Name: name}, "1") {
+			t.Errorf("the work of true/true is owned by default/%s", name)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+)
+
+var deploymentGVK = appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+// policyFor returns the BootstrapPolicy which selects the object. When no
+// policy selects it, the policy is converted from the legacy annotations and
+// legacy is true. It returns nil when neither of them is set.
+// The oldest policy wins when several of them select the object.
+func (c *Controller) policyFor(ctx context.Context, obj client.Object) (policy *bootstrappingv1alpha1.BootstrapPolicy, legacy bool, err error) {
+	policies := &bootstrappingv1alpha1.BootstrapPolicyList{}
+	if err := c.Client.List(ctx, policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil, false, err
+	}
+
+	for i := range policies.Items {
+		p := &policies.Items[i]
+		if !p.DeletionTimestamp.IsZero() || !policyMatches(p, c.gvk, obj) {
+			continue
+		}
+		if policy == nil || p.CreationTimestamp.Before(&policy.CreationTimestamp) ||
+			(p.CreationTimestamp.Equal(&policy.CreationTimestamp) && p.Name < policy.Name) {
+			policy = p
+		}
+	}
+	if policy != nil {
+		return policy, false, nil
+	}
+
+	policy = c.legacyPolicy(obj)
+	return policy, policy != nil, nil
+}
+
+// policyMatches tells whether the resource selector of the policy selects the object of the kind.
+func policyMatches(policy *bootstrappingv1alpha1.BootstrapPolicy, gvk schema.GroupVersionKind, obj metav1.Object) bool {
+	selector := policy.Spec.ResourceSelector
+	if selector.APIVersion != gvk.GroupVersion().String() || selector.Kind != gvk.Kind {
+		return false
+	}
+	if selector.Name != "" {
+		return selector.Name == obj.GetName()
+	}
+	if selector.LabelSelector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+	if err != nil {
+		klog.Errorf("Invalid label selector of BootstrapPolicy %s/%s: %v", policy.Namespace, policy.Name, err)
+		return false
+	}
+	return s.Matches(labels.Set(obj.GetLabels()))
+}
+
+// legacyPolicy converts the deprecated "deployments-*" annotations of the
+// deployment into an in-memory BootstrapPolicy, nil if none is set.
+// The annotations only ever applied to Deployments, they are ignored on the other kinds.
+func (c *Controller) legacyPolicy(deployment client.Object) *bootstrappingv1alpha1.BootstrapPolicy {
+	if c.gvk != deploymentGVK {
+		return nil
+	}
+
+	annotations := deployment.GetAnnotations()
+	policy := &bootstrappingv1alpha1.BootstrapPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: deployment.GetNamespace()},
+		Spec: bootstrappingv1alpha1.BootstrapPolicySpec{
+			ResourceSelector: bootstrappingv1alpha1.ResourceSelector{
+				APIVersion: deploymentGVK.GroupVersion().String(),
+				Kind:       deploymentGVK.Kind,
+				Name:       deployment.GetName(),
+			},
+			Mode: bootstrappingv1alpha1.DistributionModeWork,
+		},
+	}
+
+	members, hasMembers := annotations[c.annotationKey(annotationMembers)]
+	clusterSelector, hasClusterSelector := annotations[c.annotationKey(annotationClusterSelector)]
+	if v, ok := annotations[c.annotationKey(annotationGlobal)]; ok && v == "true" {
+		klog.Infof("namespace %q deployment %q global distribution.", deployment.GetNamespace(), deployment.GetName())
+	} else if hasMembers || hasClusterSelector {
+		if hasMembers {
+			policy.Spec.Placement.ClusterNames = strings.Split(members, ",")
+		}
+		if hasClusterSelector {
+			selector, err := metav1.ParseToLabelSelector(clusterSelector)
+			if err != nil {
+				klog.Errorf("Invalid cluster selector %q of namespace %q deployment %q: %v", clusterSelector, deployment.GetNamespace(), deployment.GetName(), err)
+				c.recorder.Eventf(deployment, corev1.EventTypeWarning, events.EventReasonInvalidClusterSelector,
+					"Invalid cluster selector %q: %v", clusterSelector, err)
+				return nil
+			}
+			policy.Spec.Placement.ClusterSelector = selector
+		}
+	} else {
+		return nil
+	}
+
+	if v, ok := annotations[c.annotationKey(annotationForce)]; ok && v != "true" {
+		policy.Spec.Mode = bootstrappingv1alpha1.DistributionModePropagationPolicy
+	}
+	policy.Spec.PropagateDeps = annotations[c.annotationKey(annotationPropagateDeps)] == "true"
+	klog.V(2).Infof("namespace %q deployment %q is driven by the deprecated %s/deployments-* annotations, use a BootstrapPolicy instead.",
+		deployment.GetNamespace(), deployment.GetName(), c.config.AnnotationPrefix)
+	return policy
+}
+
+// policyToObjects maps a BootstrapPolicy to the objects of the kind it selects,
+// or selected the last time its status was written.
+func (c *Controller) policyToObjects(obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*bootstrappingv1alpha1.BootstrapPolicy)
+	if !ok {
+		return nil
+	}
+
+	names := map[string]struct{}{}
+	for _, resource := range policy.Status.Resources {
+		if resource.APIVersion == c.gvk.GroupVersion().String() && resource.Kind == c.gvk.Kind {
+			names[resource.Name] = struct{}{}
+		}
+	}
+
+	list := c.newList()
+	if err := c.Client.List(context.TODO(), list, client.InNamespace(policy.Namespace)); err != nil {
+		klog.Errorf("Failed to list %s of namespace %q. error: %v", c.gvk.Kind, policy.Namespace, err)
+	} else {
+		for i := range list.Items {
+			if policyMatches(policy, c.gvk, &list.Items[i]) {
+				names[list.Items[i].GetName()] = struct{}{}
+			}
+		}
+	}
+
+	requests := make([]reconcile.Request, 0, len(names))
+	for name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: name}})
+	}
+	return requests
+}
+
+// clusterToObjects maps a Cluster which joined, left or whose labels changed
+// to the objects of the kind whose placement may include it.
+func (c *Controller) clusterToObjects(obj client.Object) []reconcile.Request {
+	policies := &bootstrappingv1alpha1.BootstrapPolicyList{}
+	if err := c.Client.List(context.TODO(), policies); err != nil {
+		klog.Errorf("Failed to list BootstrapPolicies. error: %v", err)
+		return nil
+	}
+	namespacePolicies := map[string][]*bootstrappingv1alpha1.BootstrapPolicy{}
+	for i := range policies.Items {
+		if placementMayInclude(&policies.Items[i].Spec.Placement, obj.GetName()) {
+			namespacePolicies[policies.Items[i].Namespace] = append(namespacePolicies[policies.Items[i].Namespace], &policies.Items[i])
+		}
+	}
+
+	list := c.newList()
+	if err := c.Client.List(context.TODO(), list); err != nil {
+		klog.Errorf("Failed to list %s. error: %v", c.gvk.Kind, err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		item := &list.Items[i]
+		selected := c.legacyPlacementMayInclude(item, obj.GetName())
+		for _, policy := range namespacePolicies[item.GetNamespace()] {
+			if !selected && policyMatches(policy, c.gvk, item) {
+				selected = true
+			}
+		}
+		if selected {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()}})
+		}
+	}
+	klog.V(2).Infof("cluster %q changed, resync %d %s.", obj.GetName(), len(requests), c.gvk.Kind)
+	return requests
+}
+
+// placementMayInclude tells whether the cluster is, or was before its labels
+// changed, a target of the placement. Only a list of names excludes it for sure.
+func placementMayInclude(placement *bootstrappingv1alpha1.Placement, cluster string) bool {
+	if len(placement.ClusterNames) == 0 {
+		return true
+	}
+	for _, name := range placement.ClusterNames {
+		if name == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyPlacementMayInclude is placementMayInclude for the deprecated annotations
+// of a deployment, it does not parse the cluster selector.
+func (c *Controller) legacyPlacementMayInclude(deployment client.Object, cluster string) bool {
+	if c.gvk != deploymentGVK {
+		return false
+	}
+
+	annotations := deployment.GetAnnotations()
+	if v, ok := annotations[c.annotationKey(annotationGlobal)]; ok && v == "true" {
+		return true
+	}
+	if members, ok := annotations[c.annotationKey(annotationMembers)]; ok {
+		return placementMayInclude(&bootstrappingv1alpha1.Placement{ClusterNames: strings.Split(members, ",")}, cluster)
+	}
+	_, ok := annotations[c.annotationKey(annotationClusterSelector)]
+	return ok
+}
+
+// syncPolicyStatus records the status of the object on the policy which
+// selects it, and removes it from the other policies of the namespace.
+// selected is nil when no policy selects the object anymore.
+func (c *Controller) syncPolicyStatus(ctx context.Context, key types.NamespacedName, selected *bootstrappingv1alpha1.BootstrapPolicy,
+	status *bootstrappingv1alpha1.ResourceStatus) error {
+	policies := &bootstrappingv1alpha1.BootstrapPolicyList{}
+	if err := c.Client.List(ctx, policies, client.InNamespace(key.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		// the objects of the policy write their status concurrently, a conflict is retried on the latest policy.
+		retried := false
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if retried {
+				if err := c.apiReader.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+					return err
+				}
+			}
+			retried = true
+			return c.updatePolicyStatus(ctx, policy, key, selected, status)
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update status of BootstrapPolicy %s/%s: %v", policy.Namespace, policy.Name, err)
+		}
+	}
+	return nil
+}
+
+// updatePolicyStatus writes the status of the object to the policy, when it changes it.
+func (c *Controller) updatePolicyStatus(ctx context.Context, policy *bootstrappingv1alpha1.BootstrapPolicy, key types.NamespacedName,
+	selected *bootstrappingv1alpha1.BootstrapPolicy, status *bootstrappingv1alpha1.ResourceStatus) error {
+	newStatus := policy.Status.DeepCopy()
+	newStatus.Resources = removeResourceStatus(newStatus.Resources, c.gvk, key.Name)
+	if selected != nil && policy.Name == selected.Name {
+		newStatus.Resources = append(newStatus.Resources, *status)
+		sort.Slice(newStatus.Resources, func(i, j int) bool {
+			if newStatus.Resources[i].Kind != newStatus.Resources[j].Kind {
+				return newStatus.Resources[i].Kind < newStatus.Resources[j].Kind
+			}
+			return newStatus.Resources[i].Name < newStatus.Resources[j].Name
+		})
+	} else if len(newStatus.Resources) == len(policy.Status.Resources) {
+		return nil
+	}
+	newStatus.ObservedGeneration = policy.Generation
+	setAppliedCondition(policy, newStatus)
+	setRolledOutCondition(policy, newStatus)
+
+	if equality.Semantic.DeepEqual(newStatus, &policy.Status) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would update status of BootstrapPolicy %s/%s: %+v", policy.Namespace, policy.Name, newStatus.Resources)
+		return nil
+	}
+	policy.Status = *newStatus
+	return c.Client.Status().Update(ctx, policy)
+}
+
+// removeResourceStatus returns resources without the entry of the object of the kind.
+func removeResourceStatus(resources []bootstrappingv1alpha1.ResourceStatus, gvk schema.GroupVersionKind, name string) []bootstrappingv1alpha1.ResourceStatus {
+	kept := resources[:0]
+	for _, resource := range resources {
+		if resource.APIVersion == gvk.GroupVersion().String() && resource.Kind == gvk.Kind && resource.Name == name {
+			continue
+		}
+		kept = append(kept, resource)
+	}
+	return kept
+}
+
+// setAppliedCondition sets the Applied condition from the spec and the resources of the status.
+func setAppliedCondition(policy *bootstrappingv1alpha1.BootstrapPolicy, status *bootstrappingv1alpha1.BootstrapPolicyStatus) {
+	condition := metav1.Condition{
+		Type:               bootstrappingv1alpha1.ConditionTypeApplied,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: policy.Generation,
+		Reason:             bootstrappingv1alpha1.ReasonApplySucceeded,
+		Message:            fmt.Sprintf("%d resources distributed", len(status.Resources)),
+	}
+
+	var failed []string
+	for _, resource := range status.Resources {
+		if resource.Error != "" {
+			failed = append(failed, resource.Kind+"/"+resource.Name)
+		}
+	}
+	if errs := bootstrappingv1alpha1.ValidateBootstrapPolicy(policy); len(errs) != 0 {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, bootstrappingv1alpha1.ReasonInvalidSpec, errs.ToAggregate().Error()
+	} else if len(failed) != 0 {
+		condition.Status, condition.Reason = metav1.ConditionFalse, bootstrappingv1alpha1.ReasonApplyFailed
+		condition.Message = fmt.Sprintf("failed to distribute %s", strings.Join(failed, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// propagationPlacement converts the placement to the one of a PropagationPolicy
+// targeting the clusters. Divided replicas are weighted by the static weights,
+// every cluster weighs the same by default, or by the dynamic weight.
+func propagationPlacement(placement *bootstrappingv1alpha1.Placement, clusters []string) policy1alpha1.Placement {
+	result := policy1alpha1.Placement{
+		ClusterAffinity: &policy1alpha1.ClusterAffinity{
+			ClusterNames: clusters,
+		},
+		ClusterTolerations: placement.ClusterTolerations,
+	}
+	for _, constraint := range placement.SpreadConstraints {
+		result.SpreadConstraints = append(result.SpreadConstraints, policy1alpha1.SpreadConstraint{
+			SpreadByField: policy1alpha1.SpreadFieldValue(constraint.SpreadByField),
+			SpreadByLabel: constraint.SpreadByLabel,
+			MaxGroups:     constraint.MaxGroups,
+			MinGroups:     constraint.MinGroups,
+		})
+	}
+
+	if placement.SchedulingType() == bootstrappingv1alpha1.ReplicaSchedulingTypeDuplicated {
+		result.ReplicaScheduling = &policy1alpha1.ReplicaSchedulingStrategy{
+			ReplicaSchedulingType: policy1alpha1.ReplicaSchedulingTypeDuplicated,
+		}
+		return result
+	}
+
+	preference := &policy1alpha1.ClusterPreferences{}
+	scheduling := placement.ReplicaScheduling
+	switch {
+	case scheduling != nil && scheduling.DynamicWeight != "":
+		preference.DynamicWeight = policy1alpha1.DynamicWeightFactor(scheduling.DynamicWeight)
+	case scheduling != nil && len(scheduling.StaticWeights) != 0:
+		weights := map[string]int64{}
+		for _, weight := range scheduling.StaticWeights {
+			weights[weight.ClusterName] = weight.Weight
+		}
+		for _, cluster := range clusters {
+			weight, ok := weights[cluster]
+			if !ok {
+				weight = 1
+			}
+			preference.StaticWeightList = append(preference.StaticWeightList, policy1alpha1.StaticClusterWeight{
+				TargetCluster: policy1alpha1.ClusterAffinity{ClusterNames: []string{cluster}},
+				Weight:        weight,
+			})
+		}
+	default:
+		preference.StaticWeightList = []policy1alpha1.StaticClusterWeight{
+			{
+				TargetCluster: policy1alpha1.ClusterAffinity{
+					ClusterNames: clusters,
+				},
+				Weight: 1,
+			},
+		}
+	}
+	result.ReplicaScheduling = &policy1alpha1.ReplicaSchedulingStrategy{
+		ReplicaDivisionPreference: policy1alpha1.ReplicaDivisionPreferenceWeighted,
+		ReplicaSchedulingType:     policy1alpha1.ReplicaSchedulingTypeDivided,
+		WeightPreference:          preference,
+	}
+	return result
+}
@@ -0,0 +1,350 @@
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policy1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+)
+
+func TestPolicyMatches(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "nginx",
+		Labels:    map[string]string{"app": "nginx"},
+	}}
+
+	tests := []struct {
+		name     string
+		gvk      schema.GroupVersionKind
+		selector bootstrappingv1alpha1.ResourceSelector
+		want     bool
+	}{
+		{
+			name:     "by name",
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx"},
+			want:     true,
+		},
+		{
+			name:     "other name",
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment", Name: "redis"},
+		},
+		{
+			name: "by label",
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}},
+			want: true,
+		},
+		{
+			name: "other label",
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redis"}}},
+		},
+		{
+			name:     "other kind",
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "nginx"},
+		},
+		{
+			name:     "configured kind",
+			gvk:      corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "v1", Kind: "ConfigMap", Name: "nginx"},
+			want:     true,
+		},
+		{
+			name:     "other group",
+			gvk:      schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Deployment"},
+			selector: bootstrappingv1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gvk := tt.gvk
+			if gvk.Empty() {
+				gvk = deploymentGVK
+			}
+			policy := &bootstrappingv1alpha1.BootstrapPolicy{Spec: bootstrappingv1alpha1.BootstrapPolicySpec{ResourceSelector: tt.selector}}
+			if got := policyMatches(policy, gvk, deployment); got != tt.want {
+				t.Errorf("policyMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantNil      bool
+		wantClusters []string
+		wantMode     bootstrappingv1alpha1.DistributionMode
+		wantDeps     bool
+	}{
+		{
+			name:    "no annotations",
+			wantNil: true,
+		},
+		{
+			name:        "global",
+			annotations: map[string]string{"bootstrapping.karmada.io/deployments-global": "true"},
+			wantMode:    bootstrappingv1alpha1.DistributionModeWork,
+		},
+		{
+			name: "members with propagation policy",
+			annotations: map[string]string{
+				"bootstrapping.karmada.io/deployments-members": "member1,member2",
+				"bootstrapping.karmada.io/deployments-force":   "false",
+			},
+			wantClusters: []string{"member1", "member2"},
+			wantMode:     bootstrappingv1alpha1.DistributionModePropagationPolicy,
+		},
+		{
+			name:        "cluster selector",
+			annotations: map[string]string{"bootstrapping.karmada.io/deployments-cluster-selector": "region=east"},
+			wantMode:    bootstrappingv1alpha1.DistributionModeWork,
+		},
+		{
+			name: "dependencies",
+			annotations: map[string]string{
+				"bootstrapping.karmada.io/deployments-global":         "true",
+				"bootstrapping.karmada.io/deployments-propagate-deps": "true",
+			},
+			wantMode: bootstrappingv1alpha1.DistributionModeWork,
+			wantDeps: true,
+		},
+	}
+
+	c := &Controller{gvk: deploymentGVK, config: configv1alpha1.DeploymentControllerConfiguration{AnnotationPrefix: configv1alpha1.DefaultDeploymentAnnotationPrefix}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx", Annotations: tt.annotations}}
+			policy := c.legacyPolicy(deployment)
+			if (policy == nil) != tt.wantNil {
+				t.Fatalf("legacyPolicy() = %v, want nil %v", policy, tt.wantNil)
+			}
+			if policy == nil {
+				return
+			}
+			if !policyMatches(policy, c.gvk, deployment) {
+				t.Errorf("legacy policy does not select its own deployment")
+			}
+			if got := policy.Spec.Placement.ClusterNames; !reflect.DeepEqual(got, tt.wantClusters) {
+				t.Errorf("ClusterNames = %v, want %v", got, tt.wantClusters)
+			}
+			if got := policy.Spec.DistributionMode(); got != tt.wantMode {
+				t.Errorf("Mode = %q, want %q", got, tt.wantMode)
+			}
+			if got := policy.Spec.PropagateDeps; got != tt.wantDeps {
+				t.Errorf("PropagateDeps = %v, want %v", got, tt.wantDeps)
+			}
+		})
+	}
+
+	configMaps := &Controller{gvk: corev1.SchemeGroupVersion.WithKind("ConfigMap"), config: c.config}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx",
+		Annotations: map[string]string{"bootstrapping.karmada.io/deployments-global": "true"}}}
+	if policy := configMaps.legacyPolicy(configMap); policy != nil {
+		t.Errorf("legacyPolicy() = %v for a ConfigMap, want nil", policy)
+	}
+}
+
+func TestSkipClusters(t *testing.T) {
+	clusters := []clusterv1alpha1.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "member1", Labels: map[string]string{"region": "east", "env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "member2", Labels: map[string]string{"region": "east", "env": "dev"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "member3", Labels: map[string]string{"region": "west", "env": "prod"}}},
+	}
+
+	tests := []struct {
+		name      string
+		placement bootstrappingv1alpha1.Placement
+		want      []string
+	}{
+		{
+			name: "all clusters",
+			want: []string{"member1", "member2", "member3"},
+		},
+		{
+			name:      "cluster names",
+			placement: bootstrappingv1alpha1.Placement{ClusterNames: []string{"member2", "member4"}},
+			want:      []string{"member2"},
+		},
+		{
+			name: "match expressions",
+			placement: bootstrappingv1alpha1.Placement{ClusterSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"env": "prod"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"east", "north"}},
+				},
+			}},
+			want: []string{"member1"},
+		},
+		{
+			name: "cluster names and selector",
+			placement: bootstrappingv1alpha1.Placement{
+				ClusterNames:    []string{"member1", "member3"},
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "west"}},
+			},
+			want: []string{"member3"},
+		},
+	}
+
+	c := &Controller{gvk: deploymentGVK, recorder: record.NewFakeRecorder(10)}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &bootstrappingv1alpha1.BootstrapPolicy{Spec: bootstrappingv1alpha1.BootstrapPolicySpec{Placement: tt.placement}}
+			if got := c.skipClusters(deployment, policy, nil, clusters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("skipClusters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacementMayInclude(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		placement   *bootstrappingv1alpha1.Placement
+		want        bool
+	}{
+		{
+			name:      "all clusters",
+			placement: &bootstrappingv1alpha1.Placement{},
+			want:      true,
+		},
+		{
+			name:      "listed",
+			placement: &bootstrappingv1alpha1.Placement{ClusterNames: []string{"member1", "member2"}},
+			want:      true,
+		},
+		{
+			name:      "not listed",
+			placement: &bootstrappingv1alpha1.Placement{ClusterNames: []string{"member1"}},
+		},
+		{
+			name:      "cluster selector",
+			placement: &bootstrappingv1alpha1.Placement{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "east"}}},
+			want:      true,
+		},
+		{
+			name:        "legacy global",
+			annotations: map[string]string{"bootstrapping.karmada.io/deployments-global": "true"},
+			want:        true,
+		},
+		{
+			name:        "legacy members",
+			annotations: map[string]string{"bootstrapping.karmada.io/deployments-members": "member1"},
+		},
+		{
+			name:        "legacy cluster selector",
+			annotations: map[string]string{"bootstrapping.karmada.io/deployments-cluster-selector": "region=east"},
+			want:        true,
+		},
+		{
+			name: "no annotations",
+		},
+	}
+
+	c := &Controller{gvk: deploymentGVK, config: configv1alpha1.DeploymentControllerConfiguration{AnnotationPrefix: configv1alpha1.DefaultDeploymentAnnotationPrefix}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			if tt.placement != nil {
+				got = placementMayInclude(tt.placement, "member2")
+			} else {
+				deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx", Annotations: tt.annotations}}
+				got = c.legacyPlacementMayInclude(deployment, "member2")
+			}
+			if got != tt.want {
+				t.Errorf("placement may include member2 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropagationPlacement(t *testing.T) {
+	clusters := []string{"member1", "member2"}
+	tests := []struct {
+		name       string
+		scheduling *bootstrappingv1alpha1.ReplicaScheduling
+		want       *policy1alpha1.ReplicaSchedulingStrategy
+	}{
+		{
+			name: "default",
+			want: &policy1alpha1.ReplicaSchedulingStrategy{
+				ReplicaSchedulingType:     policy1alpha1.ReplicaSchedulingTypeDivided,
+				ReplicaDivisionPreference: policy1alpha1.ReplicaDivisionPreferenceWeighted,
+				WeightPreference: &policy1alpha1.ClusterPreferences{StaticWeightList: []policy1alpha1.StaticClusterWeight{
+					{TargetCluster: policy1alpha1.ClusterAffinity{ClusterNames: clusters}, Weight: 1},
+				}},
+			},
+		},
+		{
+			name:       "duplicated",
+			scheduling: &bootstrappingv1alpha1.ReplicaScheduling{Type: bootstrappingv1alpha1.ReplicaSchedulingTypeDuplicated},
+			want:       &policy1alpha1.ReplicaSchedulingStrategy{ReplicaSchedulingType: policy1alpha1.ReplicaSchedulingTypeDuplicated},
+		},
+		{
+			name: "static weights",
+			scheduling: &bootstrappingv1alpha1.ReplicaScheduling{StaticWeights: []bootstrappingv1alpha1.ClusterWeight{
+				{ClusterName: "member2", Weight: 3},
+				{ClusterName: "member3", Weight: 2},
+			}},
+			want: &policy1alpha1.ReplicaSchedulingStrategy{
+				ReplicaSchedulingType:     policy1alpha1.ReplicaSchedulingTypeDivided,
+				ReplicaDivisionPreference: policy1alpha1.ReplicaDivisionPreferenceWeighted,
+				WeightPreference: &policy1alpha1.ClusterPreferences{StaticWeightList: []policy1alpha1.StaticClusterWeight{
+					{TargetCluster: policy1alpha1.ClusterAffinity{ClusterNames: []string{"member1"}}, Weight: 1},
+					{TargetCluster: policy1alpha1.ClusterAffinity{ClusterNames: []string{"member2"}}, Weight: 3},
+				}},
+			},
+		},
+		{
+			name: "dynamic weight",
+			scheduling: &bootstrappingv1alpha1.ReplicaScheduling{
+				Type:          bootstrappingv1alpha1.ReplicaSchedulingTypeDivided,
+				DynamicWeight: bootstrappingv1alpha1.DynamicWeightByAvailableReplicas,
+			},
+			want: &policy1alpha1.ReplicaSchedulingStrategy{
+				ReplicaSchedulingType:     policy1alpha1.ReplicaSchedulingTypeDivided,
+				ReplicaDivisionPreference: policy1alpha1.ReplicaDivisionPreferenceWeighted,
+				WeightPreference:          &policy1alpha1.ClusterPreferences{DynamicWeight: policy1alpha1.DynamicWeightByAvailableReplicas},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement := &bootstrappingv1alpha1.Placement{
+				ReplicaScheduling:  tt.scheduling,
+				SpreadConstraints:  []bootstrappingv1alpha1.SpreadConstraint{{SpreadByField: bootstrappingv1alpha1.SpreadByFieldRegion, MaxGroups: 2}},
+				ClusterTolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+			}
+			got := propagationPlacement(placement, clusters)
+			if !reflect.DeepEqual(got.ReplicaScheduling, tt.want) {
+				t.Errorf("ReplicaScheduling = %+v, want %+v", got.ReplicaScheduling, tt.want)
+			}
+			if !reflect.DeepEqual(got.ClusterAffinity.ClusterNames, clusters) {
+				t.Errorf("ClusterNames = %v, want %v", got.ClusterAffinity.ClusterNames, clusters)
+			}
+			wantSpread := []policy1alpha1.SpreadConstraint{{SpreadByField: policy1alpha1.SpreadByFieldRegion, MaxGroups: 2}}
+			if !reflect.DeepEqual(got.SpreadConstraints, wantSpread) {
+				t.Errorf("SpreadConstraints = %v, want %v", got.SpreadConstraints, wantSpread)
+			}
+			if !reflect.DeepEqual(got.ClusterTolerations, placement.ClusterTolerations) {
+				t.Errorf("ClusterTolerations = %v, want %v", got.ClusterTolerations, placement.ClusterTolerations)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+)
+
+// A staged rollout updates the Works of the target clusters wave by wave. The
+// revisions of the workload are recorded in ControllerRevisions controlled by
+// the object, the one being rolled out is marked Complete once every wave rolled
+// it out, or RolledBack when a wave failed and the Works were reverted to the
+// revisions they held before.
+//
+// A revision is the workload of the object before the overrides of the clusters
+// are applied, the ones in effect are applied again when a revision is written,
+// rolled back included. The revisions are hashed from the object only, a change
+// of the OverridePolicies is not a new revision and is not staged.
+
+const (
+	// annotationRevision is the revision of the workload of a Work.
+	annotationRevision = "bootstrapping.karmada.io/revision"
+	// annotationPreviousRevision is the revision the Work held before its current one.
+	annotationPreviousRevision = "bootstrapping.karmada.io/previous-revision"
+	// annotationRevisionTime is when the Work was updated to its revision, in RFC 3339.
+	annotationRevisionTime = "bootstrapping.karmada.io/revision-time"
+
+	// annotationRolloutPhase marks the ControllerRevision of a revision which was
+	// rolled out, Complete, or rolled back, RolledBack.
+	annotationRolloutPhase = "bootstrapping.karmada.io/rollout-phase"
+	// annotationRolloutMessage explains why the revision was rolled back.
+	annotationRolloutMessage = "bootstrapping.karmada.io/rollout-message"
+)
+
+// workloadRevision is a revision of the workload of an object.
+type workloadRevision struct {
+	name     string
+	workload *unstructured.Unstructured
+}
+
+// currentRevision returns the revision of the object as it is. The status
+// annotation the controller writes and the fields the apiserver sets on every
+// write are left out, each write of the object would update its Works otherwise.
+func (c *Controller) currentRevision(obj *unstructured.Unstructured) (*workloadRevision, error) {
+	workload := obj.DeepCopy()
+	unstructured.RemoveNestedField(workload.Object, "status")
+	workload.SetManagedFields(nil)
+	workload.SetResourceVersion("")
+	annotations := workload.GetAnnotations()
+	delete(annotations, c.annotationKey(annotationStatus))
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	workload.SetAnnotations(annotations)
+	name, err := revisionName(workload)
+	if err != nil {
+		return nil, err
+	}
+	return &workloadRevision{name: name, workload: workload}, nil
+}
+
+// revisionName hashes the labels, the annotations and the fields of the workload
+// other than its metadata and status, the rest of the metadata changes with
+// every write of the object.
+func revisionName(workload *unstructured.Unstructured) (string, error) {
+	template := map[string]interface{}{}
+	for key, value := range workload.Object {
+		if key != "metadata" && key != "status" {
+			template[key] = value
+		}
+	}
+	metadata := map[string]interface{}{}
+	if labels := workload.GetLabels(); len(labels) != 0 {
+		metadata["labels"] = labels
+	}
+	if annotations := workload.GetAnnotations(); len(annotations) != 0 {
+		metadata["annotations"] = annotations
+	}
+	template["metadata"] = metadata
+
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New32a()
+	hash.Write(data)
+	return rand.SafeEncodeString(fmt.Sprint(hash.Sum32())), nil
+}
+
+// workWorkload returns the workload of the Work without the labels karmada
+// identifies the Work with.
+func workWorkload(work *workv1alpha1.Work) (*unstructured.Unstructured, error) {
+	if len(work.Spec.Workload.Manifests) == 0 {
+		return nil, fmt.Errorf("work %s/%s has no manifest", work.Namespace, work.Name)
+	}
+	workload := &unstructured.Unstructured{}
+	if err := workload.UnmarshalJSON(work.Spec.Workload.Manifests[0].Raw); err != nil {
+		return nil, err
+	}
+	labels := workload.GetLabels()
+	delete(labels, workv1alpha1.WorkNamespaceLabel)
+	delete(labels, workv1alpha1.WorkNameLabel)
+	workload.SetLabels(labels)
+	return workload, nil
+}
+
+// workRevision returns the revision of the workload of the Work. The Works
+// written before the revisions were recorded are hashed from their manifest,
+// empty when it cannot be decoded.
+func workRevision(work *workv1alpha1.Work) string {
+	if revision := work.Annotations[annotationRevision]; revision != "" {
+		return revision
+	}
+	workload, err := workWorkload(work)
+	if err != nil {
+		return ""
+	}
+	revision, err := revisionName(workload)
+	if err != nil {
+		return ""
+	}
+	return revision
+}
+
+// setRevisionAnnotations records on the Work the revision the current one held
+// and when the revision changed, they are carried over until it changes again.
+// current is nil when there is no Work yet.
+func setRevisionAnnotations(work, current *workv1alpha1.Work, now time.Time) {
+	if current != nil {
+		previous := workRevision(current)
+		if previous == work.Annotations[annotationRevision] {
+			if revision, ok := current.Annotations[annotationPreviousRevision]; ok {
+				work.Annotations[annotationPreviousRevision] = revision
+			}
+			if updated, ok := current.Annotations[annotationRevisionTime]; ok {
+				work.Annotations[annotationRevisionTime] = updated
+				return
+			}
+		} else if previous != "" {
+			work.Annotations[annotationPreviousRevision] = previous
+		}
+	}
+	work.Annotations[annotationRevisionTime] = now.UTC().Format(time.RFC3339)
+}
+
+// rolloutWave is a group of target clusters updated together.
+type rolloutWave struct {
+	name     string
+	clusters []string
+}
+
+// rolloutWaves returns the waves of the target clusters, in the order they are
+// updated. The waves of the strategy keep their order and the clusters left are
+// updated last, or the clusters are grouped by MaxUnavailable in name order.
+func rolloutWaves(rollout *bootstrappingv1alpha1.RolloutStrategy, clusters []string) []rolloutWave {
+	var waves []rolloutWave
+	if len(rollout.Waves) != 0 {
+		targets := sets.NewString(clusters...)
+		for i, wave := range rollout.Waves {
+			var members []string
+			for _, cluster := range wave.Clusters {
+				if targets.Has(cluster) {
+					members = append(members, cluster)
+					targets.Delete(cluster)
+				}
+			}
+			if len(members) == 0 {
+				continue
+			}
+			name := wave.Name
+			if name == "" {
+				name = fmt.Sprintf("wave-%d", i+1)
+			}
+			waves = append(waves, rolloutWave{name: name, clusters: members})
+		}
+		if targets.Len() != 0 {
+			waves = append(waves, rolloutWave{name: fmt.Sprintf("wave-%d", len(rollout.Waves)+1), clusters: targets.List()})
+		}
+		return waves
+	}
+
+	sorted := sets.NewString(clusters...).List()
+	size := int(rollout.MaxUnavailable)
+	if size < 1 {
+		size = 1
+	}
+	for i := 0; i < len(sorted); i += size {
+		end := i + size
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		waves = append(waves, rolloutWave{name: fmt.Sprintf("wave-%d", i/size+1), clusters: sorted[i:end]})
+	}
+	return waves
+}
+
+// statusSettleTime is how long after the update of its revision the status of a
+// Work is trusted when karmada did not write it since.
+const statusSettleTime = 30 * time.Second
+
+// waveProgress returns how many Works of the wave rolled the revision out, and
+// the time left before the deadline, counted from the last update of a Work of
+// the wave. The Works the cache has not seen updated yet are not rolled out.
+func waveProgress(works map[string]*workv1alpha1.Work, revision string, deadline time.Duration, now time.Time) (rolledOutWorks int, left time.Duration) {
+	var started time.Time
+	for cluster, work := range works {
+		if workRevision(work) != revision {
+			continue
+		}
+		ref := workReference(cluster, work)
+		updated := now
+		if ref.RevisionTime != nil {
+			updated = ref.RevisionTime.Time
+		}
+		if updated.After(started) {
+			started = updated
+		}
+		if rolledOut(&ref) && statusObserved(work, updated, now) {
+			rolledOutWorks++
+		}
+	}
+	if started.IsZero() {
+		started = now
+	}
+	return rolledOutWorks, started.Add(deadline).Sub(now)
+}
+
+// statusObserved tells whether the status of the Work was written since the
+// update of its revision. karmada reports no generation the status was observed
+// at, it is trusted statusSettleTime after the update when it was not written
+// since, e.g. when the update left the status of the workload as it was.
+func statusObserved(work *workv1alpha1.Work, since, now time.Time) bool {
+	for _, entry := range work.ManagedFields {
+		if entry.Subresource == "status" && entry.Time != nil && !entry.Time.Time.Before(since) {
+			return true
+		}
+	}
+	return now.Sub(since) >= statusSettleTime
+}
+
+// rolloutWorks writes the Works of the target clusters wave by wave. A wave is
+// updated to the revision of the object once the previous ones rolled it out,
+// the Works of the next waves are left as they are. A wave which does not roll
+// out within the progress deadline fails the rollout, which halts or is rolled
+// back. requeueAfter is set while a wave is in progress.
+func (c *Controller) rolloutWorks(ctx context.Context, obj *unstructured.Unstructured, rollout *bootstrappingv1alpha1.RolloutStrategy,
+	clusters []string) (works []bootstrappingv1alpha1.WorkReference, status *bootstrappingv1alpha1.RolloutStatus, requeueAfter time.Duration, err error) {
+	desired, err := c.currentRevision(obj)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	status = &bootstrappingv1alpha1.RolloutStatus{Revision: desired.name}
+	revisions, err := c.controllerRevisions(ctx, obj)
+	if err != nil {
+		return nil, status, 0, err
+	}
+
+	record := revisions[c.controllerRevisionName(obj, desired.name)]
+	if record != nil {
+		switch bootstrappingv1alpha1.RolloutPhase(record.Annotations[annotationRolloutPhase]) {
+		case bootstrappingv1alpha1.RolloutPhaseComplete:
+			// a revision rolled out before, e.g. the one a change is reverted to, and the
+			// clusters which became targets since are updated at once.
+			status.Phase = bootstrappingv1alpha1.RolloutPhaseComplete
+			works, err = c.buildWorks(ctx, obj, desired, clusters)
+			if err != nil {
+				return works, status, 0, err
+			}
+			return works, status, 0, c.pruneRevisions(ctx, obj, desired.name, clusters, revisions)
+		case bootstrappingv1alpha1.RolloutPhaseRolledBack:
+			status.Phase, status.Message = bootstrappingv1alpha1.RolloutPhaseRolledBack, record.Annotations[annotationRolloutMessage]
+			works, err = c.rollBack(ctx, obj, desired.name, clusters, revisions)
+			return works, status, 0, err
+		}
+	} else if record, err = c.recordRevision(ctx, obj, desired, revisions); err != nil {
+		return nil, status, 0, err
+	}
+
+	existing, err := c.targetWorks(ctx, obj, clusters)
+	if err != nil {
+		return nil, status, 0, err
+	}
+	now := time.Now()
+	waves := rolloutWaves(rollout, clusters)
+	for i, wave := range waves {
+		status.Wave = wave.name
+		if rollout.OnFailure() == bootstrappingv1alpha1.RolloutFailurePolicyRollback {
+			// the revisions the Works hold are recorded before they are replaced.
+			if err := c.recordPreviousRevisions(ctx, obj, desired.name, wave.clusters, existing, revisions); err != nil {
+				return works, status, 0, err
+			}
+		}
+		refs, err := c.buildWorks(ctx, obj, desired, wave.clusters)
+		works = append(works, refs...)
+		if err != nil {
+			return works, status, 0, err
+		}
+
+		// a wave is done once the next one started, its workloads failing later do not fail the rollout.
+		if i+1 < len(waves) && atRevision(existing, waves[i+1].clusters, desired.name) {
+			continue
+		}
+		// the status returned by the update may be the one of the previous revision, the wave is read from the cache.
+		waveWorks, err := c.targetWorks(ctx, obj, wave.clusters)
+		if err != nil {
+			return works, status, 0, err
+		}
+		rolledOutWorks, left := waveProgress(waveWorks, desired.name, rollout.ProgressDeadline(), now)
+		if rolledOutWorks == len(wave.clusters) {
+			continue
+		}
+		for _, next := range waves[i+1:] {
+			for _, cluster := range next.clusters {
+				if work := existing[cluster]; work != nil {
+					works = append(works, workReference(cluster, work))
+				}
+			}
+		}
+		if left > 0 {
+			status.Phase = bootstrappingv1alpha1.RolloutPhaseProgressing
+			status.Message = fmt.Sprintf("%d of %d clusters of wave %s rolled out", rolledOutWorks, len(wave.clusters), wave.name)
+			// the status of the Works karmada did not write since their update is trusted after statusSettleTime.
+			return works, status, shorterRequeue(left, statusSettleTime), nil
+		}
+
+		message := fmt.Sprintf("wave %s did not roll out revision %s within %v", wave.name, desired.name, rollout.ProgressDeadline())
+		if rollout.OnFailure() == bootstrappingv1alpha1.RolloutFailurePolicyHalt {
+			status.Phase, status.Message = bootstrappingv1alpha1.RolloutPhaseHalted, message
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonRolloutHalted, "Rollout halted, %s", message)
+			return works, status, 0, nil
+		}
+		// the revision is marked first, an interrupted rollback resumes from the mark.
+		if err := c.markRevision(ctx, record, bootstrappingv1alpha1.RolloutPhaseRolledBack, message); err != nil {
+			return works, status, 0, err
+		}
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonRolloutRolledBack, "Rollout rolled back, %s", message)
+		status.Phase, status.Message = bootstrappingv1alpha1.RolloutPhaseRolledBack, message
+		works, err = c.rollBack(ctx, obj, desired.name, clusters, revisions)
+		return works, status, 0, err
+	}
+
+	status.Phase, status.Wave = bootstrappingv1alpha1.RolloutPhaseComplete, ""
+	if err := c.markRevision(ctx, record, bootstrappingv1alpha1.RolloutPhaseComplete, ""); err != nil {
+		return works, status, 0, err
+	}
+	c.recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonRolloutComplete,
+		"Revision %s rolled out to %d clusters in %d waves", desired.name, len(clusters), len(waves))
+	return works, status, 0, c.pruneRevisions(ctx, obj, desired.name, clusters, revisions)
+}
+
+// atRevision tells whether a Work of the clusters holds the revision.
+func atRevision(works map[string]*workv1alpha1.Work, clusters []string, revision string) bool {
+	for _, cluster := range clusters {
+		if work := works[cluster]; work != nil && workRevision(work) == revision {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBack reverts the Works which hold the failed revision to the revision
+// they held before. The Works the failed revision did not reach are kept, and
+// so are the ones whose previous revision is unknown.
+func (c *Controller) rollBack(ctx context.Context, obj *unstructured.Unstructured, failed string, clusters []string,
+	revisions map[string]*appsv1.ControllerRevision) ([]bootstrappingv1alpha1.WorkReference, error) {
+	existing, err := c.targetWorks(ctx, obj, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	var works []bootstrappingv1alpha1.WorkReference
+	for _, cluster := range clusters {
+		work := existing[cluster]
+		if work == nil {
+			continue
+		}
+		if workRevision(work) != failed {
+			works = append(works, workReference(cluster, work))
+			continue
+		}
+		previous := work.Annotations[annotationPreviousRevision]
+		record := revisions[c.controllerRevisionName(obj, previous)]
+		if previous == "" || record == nil {
+			klog.Warningf("Work %s/%s has no previous revision of %s %s/%s to roll back to, keep it.", work.Namespace, work.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+			works = append(works, workReference(cluster, work))
+			continue
+		}
+		workload := &unstructured.Unstructured{}
+		if err := workload.UnmarshalJSON(record.Data.Raw); err != nil {
+			return works, fmt.Errorf("failed to decode ControllerRevision %s/%s: %v", record.Namespace, record.Name, err)
+		}
+		refs, err := c.buildWorks(ctx, obj, &workloadRevision{name: previous, workload: workload}, []string{cluster})
+		works = append(works, refs...)
+		if err != nil {
+			return works, err
+		}
+	}
+	return works, nil
+}
+
+// targetWorks returns the Works of the object in the execution namespaces of
+// the clusters, by cluster. The Works not written for the object are left out.
+func (c *Controller) targetWorks(ctx context.Context, obj *unstructured.Unstructured, clusters []string) (map[string]*workv1alpha1.Work, error) {
+	workName := names.GenerateWorkName(c.gvk.Kind, obj.GetName(), obj.GetNamespace())
+	works := map[string]*workv1alpha1.Work{}
+	for _, cluster := range clusters {
+		work := &workv1alpha1.Work{}
+		if err := c.Client.Get(ctx, client.ObjectKey{Namespace: names.GenerateExecutionSpaceName(cluster), Name: workName}, work); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if isOwnedWork(work, client.ObjectKeyFromObject(obj), obj.GetUID()) {
+			works[cluster] = work
+		}
+	}
+	return works, nil
+}
+
+// controllerRevisionName returns the name of the ControllerRevision of the revision of the object.
+func (c *Controller) controllerRevisionName(obj client.Object, revision string) string {
+	return names.GenerateWorkName(c.gvk.Kind, obj.GetName(), obj.GetNamespace()) + "-" + revision
+}
+
+// controllerRevisions returns the ControllerRevisions controlled by the object, by name.
+func (c *Controller) controllerRevisions(ctx context.Context, obj *unstructured.Unstructured) (map[string]*appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := c.Client.List(ctx, list, client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels(ownerLabels(client.ObjectKeyFromObject(obj)))); err != nil {
+		return nil, err
+	}
+	revisions := map[string]*appsv1.ControllerRevision{}
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], obj) {
+			revisions[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+	return revisions, nil
+}
+
+// recordRevision writes the ControllerRevision of the revision of the object and
+// adds it to revisions.
+func (c *Controller) recordRevision(ctx context.Context, obj *unstructured.Unstructured, revision *workloadRevision,
+	revisions map[string]*appsv1.ControllerRevision) (*appsv1.ControllerRevision, error) {
+	// the workload is written back in the Works on a rollback, the fields the apiserver sets are left out.
+	workload := revision.workload.DeepCopy()
+	unstructured.RemoveNestedField(workload.Object, "status")
+	workload.SetManagedFields(nil)
+	workload.SetResourceVersion("")
+	data, err := workload.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var last int64
+	for _, record := range revisions {
+		if record.Revision > last {
+			last = record.Revision
+		}
+	}
+	record := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       obj.GetNamespace(),
+			Name:            c.controllerRevisionName(obj, revision.name),
+			Labels:          ownerLabels(client.ObjectKeyFromObject(obj)),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(obj, c.gvk)},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: last + 1,
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would record revision %s of %s %s/%s in ControllerRevision %s", revision.name, c.gvk.Kind, obj.GetNamespace(), obj.GetName(), record.Name)
+		return record, nil
+	}
+
+	if err := c.Client.Create(ctx, record); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		// the cache has not seen the ControllerRevision yet.
+		if err := c.apiReader.Get(ctx, client.ObjectKeyFromObject(record), record); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(record, obj) {
+			return nil, fmt.Errorf("ControllerRevision %s/%s exists and is not controlled by %s %s: %w", record.Namespace, record.Name, c.gvk.Kind, obj.GetName(), errNotOwned)
+		}
+	} else {
+		klog.V(2).Infof("Record revision %s of %s %s/%s in ControllerRevision %s.", revision.name, c.gvk.Kind, obj.GetNamespace(), obj.GetName(), record.Name)
+	}
+	revisions[record.Name] = record
+	return record, nil
+}
+
+// recordPreviousRevisions records the revisions the Works of the clusters hold
+// before they are updated to the desired one, so that they can be rolled back.
+// The workload of a Work the overrides of its cluster were applied to is not the
+// one of its revision, such a revision is only known when the object held it.
+func (c *Controller) recordPreviousRevisions(ctx context.Context, obj *unstructured.Unstructured, desired string, clusters []string,
+	works map[string]*workv1alpha1.Work, revisions map[string]*appsv1.ControllerRevision) error {
+	for _, cluster := range clusters {
+		work := works[cluster]
+		if work == nil {
+			continue
+		}
+		revision := workRevision(work)
+		if revision == "" || revision == desired || revisions[c.controllerRevisionName(obj, revision)] != nil {
+			continue
+		}
+		if _, ok := work.Annotations[karmadautil.AppliedClusterOverrides]; ok {
+			continue
+		}
+		if _, ok := work.Annotations[karmadautil.AppliedOverrides]; ok {
+			continue
+		}
+		workload, err := workWorkload(work)
+		if err != nil {
+			return err
+		}
+		if _, err := c.recordRevision(ctx, obj, &workloadRevision{name: revision, workload: workload}, revisions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markRevision marks the ControllerRevision with the phase the rollout of its revision ended in.
+func (c *Controller) markRevision(ctx context.Context, record *appsv1.ControllerRevision, phase bootstrappingv1alpha1.RolloutPhase, message string) error {
+	if record.Annotations[annotationRolloutPhase] == string(phase) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would mark ControllerRevision %s/%s %s", record.Namespace, record.Name, phase)
+		return nil
+	}
+	patch := client.MergeFrom(record.DeepCopy())
+	if record.Annotations == nil {
+		record.Annotations = map[string]string{}
+	}
+	record.Annotations[annotationRolloutPhase] = string(phase)
+	if message != "" {
+		record.Annotations[annotationRolloutMessage] = message
+	}
+	return c.Client.Patch(ctx, record, patch)
+}
+
+// pruneRevisions deletes the ControllerRevisions of the revisions neither the
+// Works of the clusters hold nor can be rolled back to, once the desired one is
+// rolled out.
+func (c *Controller) pruneRevisions(ctx context.Context, obj *unstructured.Unstructured, desired string, clusters []string,
+	revisions map[string]*appsv1.ControllerRevision) error {
+	works, err := c.targetWorks(ctx, obj, clusters)
+	if err != nil {
+		return err
+	}
+	kept := sets.NewString(c.controllerRevisionName(obj, desired))
+	for _, work := range works {
+		kept.Insert(c.controllerRevisionName(obj, workRevision(work)))
+		if previous := work.Annotations[annotationPreviousRevision]; previous != "" {
+			kept.Insert(c.controllerRevisionName(obj, previous))
+		}
+	}
+
+	var errs []error
+	for name, record := range revisions {
+		if kept.Has(name) {
+			continue
+		}
+		if c.dryRun {
+			klog.Infof("[dry-run] would delete ControllerRevision %s/%s", record.Namespace, record.Name)
+			continue
+		}
+		if err := c.Client.Delete(ctx, record); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		klog.V(2).Infof("Delete ControllerRevision %s/%s of %s %s/%s.", record.Namespace, record.Name, c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+	}
+	return utilerrors.NewAggregate(errs)
+}
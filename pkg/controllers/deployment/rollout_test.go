@@ -0,0 +1,151 @@
+package deployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+)
+
+func TestRolloutWaves(t *testing.T) {
+	clusters := []string{"member3", "member1", "member4", "member2"}
+	tests := []struct {
+		name    string
+		rollout *bootstrappingv1alpha1.RolloutStrategy
+		want    []rolloutWave
+	}{
+		{
+			name: "waves",
+			rollout: &bootstrappingv1alpha1.RolloutStrategy{Waves: []bootstrappingv1alpha1.RolloutWave{
+				{Name: "canary", Clusters: []string{"member2"}},
+				{Clusters: []string{"member5"}},
+				{Clusters: []string{"member4", "member1"}},
+			}},
+			want: []rolloutWave{
+				{name: "canary", clusters: []string{"member2"}},
+				{name: "wave-3", clusters: []string{"member4", "member1"}},
+				{name: "wave-4", clusters: []string{"member3"}},
+			},
+		},
+		{
+			name:    "max unavailable",
+			rollout: &bootstrappingv1alpha1.RolloutStrategy{MaxUnavailable: 3},
+			want: []rolloutWave{
+				{name: "wave-1", clusters: []string{"member1", "member2", "member3"}},
+				{name: "wave-2", clusters: []string{"member4"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rolloutWaves(tt.rollout, clusters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rolloutWaves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaveProgress(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	work := func(revision string, updated, statusWritten time.Time) *workv1alpha1.Work {
+		return &workv1alpha1.Work{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotationRevision: revision, annotationRevisionTime: updated.Format(time.RFC3339)},
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "karmada-controller-manager", Subresource: "status", Time: &metav1.Time{Time: statusWritten}},
+				},
+			},
+			Status: workv1alpha1.WorkStatus{Conditions: []metav1.Condition{{Type: workv1alpha1.WorkApplied, Status: metav1.ConditionTrue}}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		works     map[string]*workv1alpha1.Work
+		wantCount int
+		wantLeft  time.Duration
+	}{
+		{
+			name: "status written since the update",
+			works: map[string]*workv1alpha1.Work{
+				"member1": work("r2", now.Add(-time.Minute), now.Add(-50*time.Second)),
+				"member2": work("r2", now.Add(-2*time.Minute), now.Add(-time.Second)),
+			},
+			wantCount: 2,
+			wantLeft:  9 * time.Minute,
+		},
+		{
+			name: "status of the previous revision",
+			works: map[string]*workv1alpha1.Work{
+				"member1": work("r2", now.Add(-10*time.Second), now.Add(-time.Minute)),
+			},
+			wantLeft: 10*time.Minute - 10*time.Second,
+		},
+		{
+			name: "status not written since the settle time",
+			works: map[string]*workv1alpha1.Work{
+				"member1": work("r2", now.Add(-time.Minute), now.Add(-2*time.Minute)),
+			},
+			wantCount: 1,
+			wantLeft:  9 * time.Minute,
+		},
+		{
+			name: "update not seen yet",
+			works: map[string]*workv1alpha1.Work{
+				"member1": work("r1", now.Add(-time.Hour), now.Add(-time.Hour)),
+			},
+			wantLeft: 10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, left := waveProgress(tt.works, "r2", 10*time.Minute, now)
+			if count != tt.wantCount || left != tt.wantLeft {
+				t.Errorf("waveProgress() = %d, %v, want %d, %v", count, left, tt.wantCount, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestSetRevisionAnnotations(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name    string
+		current *workv1alpha1.Work
+		want    map[string]string
+	}{
+		{
+			name: "new work",
+			want: map[string]string{annotationRevision: "r2", annotationRevisionTime: now.Format(time.RFC3339)},
+		},
+		{
+			name: "same revision",
+			current: &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				annotationRevision: "r2", annotationPreviousRevision: "r1", annotationRevisionTime: earlier,
+			}}},
+			want: map[string]string{annotationRevision: "r2", annotationPreviousRevision: "r1", annotationRevisionTime: earlier},
+		},
+		{
+			name: "new revision",
+			current: &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				annotationRevision: "r1", annotationPreviousRevision: "r0", annotationRevisionTime: earlier,
+			}}},
+			want: map[string]string{annotationRevision: "r2", annotationPreviousRevision: "r1", annotationRevisionTime: now.Format(time.RFC3339)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationRevision: "r2"}}}
+			setRevisionAnnotations(work, tt.current, now)
+			if !reflect.DeepEqual(work.Annotations, tt.want) {
+				t.Errorf("annotations = %v, want %v", work.Annotations, tt.want)
+			}
+		})
+	}
+}
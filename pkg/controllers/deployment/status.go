@@ -0,0 +1,198 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+
+	bootstrappingv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/bootstrapping/v1alpha1"
+)
+
+// replicaStatus holds the replicas of the status karmada reflects from the
+// workload of a member cluster, e.g. the one of a Deployment or a StatefulSet.
+type replicaStatus struct {
+	Replicas          *int32 `json:"replicas,omitempty"`
+	UpdatedReplicas   *int32 `json:"updatedReplicas,omitempty"`
+	ReadyReplicas     *int32 `json:"readyReplicas,omitempty"`
+	AvailableReplicas *int32 `json:"availableReplicas,omitempty"`
+}
+
+// workReference returns the reference of the Work with the state karmada
+// reported for its workload.
+func workReference(cluster string, work *workv1alpha1.Work) bootstrappingv1alpha1.WorkReference {
+	ref := bootstrappingv1alpha1.WorkReference{
+		Cluster:   cluster,
+		Namespace: work.Namespace,
+		Name:      work.Name,
+		Revision:  work.Annotations[annotationRevision],
+		Applied:   metav1.ConditionUnknown,
+	}
+	if updated, err := time.Parse(time.RFC3339, work.Annotations[annotationRevisionTime]); err == nil {
+		ref.RevisionTime = &metav1.Time{Time: updated}
+	}
+	if applied := meta.FindStatusCondition(work.Status.Conditions, workv1alpha1.WorkApplied); applied != nil {
+		ref.Applied = applied.Status
+		if applied.Status != metav1.ConditionTrue {
+			ref.Message = applied.Message
+		}
+	}
+
+	for _, manifest := range work.Status.ManifestStatuses {
+		// the Work holds a single manifest.
+		if manifest.Identifier.Ordinal != 0 {
+			continue
+		}
+		ref.Health = string(manifest.Health)
+		if manifest.Status == nil || len(manifest.Status.Raw) == 0 {
+			break
+		}
+		status := &replicaStatus{}
+		if err := json.Unmarshal(manifest.Status.Raw, status); err != nil {
+			klog.V(2).Infof("Failed to decode the status of work %s/%s: %v", work.Namespace, work.Name, err)
+			break
+		}
+		if status.Replicas == nil {
+			break
+		}
+		// the zero counts are omitted from the status.
+		ref.Replicas = status.Replicas
+		ref.UpdatedReplicas = replicasOrZero(status.UpdatedReplicas)
+		ref.ReadyReplicas = replicasOrZero(status.ReadyReplicas)
+		ref.AvailableReplicas = replicasOrZero(status.AvailableReplicas)
+	}
+	return ref
+}
+
+func replicasOrZero(replicas *int32) *int32 {
+	if replicas == nil {
+		return new(int32)
+	}
+	return replicas
+}
+
+// rolledOut tells whether the Work is applied and all the replicas of its
+// workload, if it has any, are updated and available.
+func rolledOut(ref *bootstrappingv1alpha1.WorkReference) bool {
+	if ref.Applied != metav1.ConditionTrue || ref.Health == string(workv1alpha1.ResourceUnhealthy) {
+		return false
+	}
+	if ref.Replicas == nil {
+		return true
+	}
+	return *ref.UpdatedReplicas == *ref.Replicas && *ref.AvailableReplicas == *ref.Replicas
+}
+
+// resourceRolledOut tells whether every Work of the resource is rolled out, and
+// its staged rollout is complete.
+func resourceRolledOut(status *bootstrappingv1alpha1.ResourceStatus) bool {
+	if status.Error != "" || len(status.Works) == 0 {
+		return false
+	}
+	if status.Rollout != nil && status.Rollout.Phase != bootstrappingv1alpha1.RolloutPhaseComplete {
+		return false
+	}
+	for i := range status.Works {
+		if !rolledOut(&status.Works[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// setRolledOutCondition sets the RolledOut condition from the resources of the
+// status distributed as Works, it is removed when there are none.
+func setRolledOutCondition(policy *bootstrappingv1alpha1.BootstrapPolicy, status *bootstrappingv1alpha1.BootstrapPolicyStatus) {
+	total, pending := 0, 0
+	for i := range status.Resources {
+		if len(status.Resources[i].Works) == 0 {
+			continue
+		}
+		total++
+		if !status.Resources[i].RolledOut {
+			pending++
+		}
+	}
+	if total == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, bootstrappingv1alpha1.ConditionTypeRolledOut)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               bootstrappingv1alpha1.ConditionTypeRolledOut,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: policy.Generation,
+		Reason:             bootstrappingv1alpha1.ReasonRolloutComplete,
+		Message:            fmt.Sprintf("%d resources rolled out in every member cluster", total),
+	}
+	if pending != 0 {
+		condition.Status, condition.Reason = metav1.ConditionFalse, bootstrappingv1alpha1.ReasonRolloutInProgress
+		condition.Message = fmt.Sprintf("%d of %d resources are not rolled out in every member cluster", pending, total)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// syncLegacyStatus records the status of an object distributed by the legacy
+// annotations in its status annotation, the annotation is removed when status is nil.
+func (c *Controller) syncLegacyStatus(ctx context.Context, obj *unstructured.Unstructured, status *bootstrappingv1alpha1.ResourceStatus) error {
+	key := c.annotationKey(annotationStatus)
+	current, ok := obj.GetAnnotations()[key]
+	var value string
+	if status != nil {
+		raw, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		value = string(raw)
+	}
+	if (status == nil && !ok) || (status != nil && ok && current == value) {
+		return nil
+	}
+	if c.dryRun {
+		klog.Infof("[dry-run] would update annotation %s of %s %s/%s: %s", key, c.gvk.Kind, obj.GetNamespace(), obj.GetName(), value)
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	annotations := obj.GetAnnotations()
+	if status == nil {
+		delete(annotations, key)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = value
+	}
+	obj.SetAnnotations(annotations)
+	return c.Client.Patch(ctx, obj, patch)
+}
+
+// workToObject maps a Work to the object it was written for, its status is
+// aggregated on the policy which selects the object.
+func (c *Controller) workToObject(obj client.Object) []reconcile.Request {
+	work, ok := obj.(*workv1alpha1.Work)
+	if !ok || len(work.Spec.Workload.Manifests) == 0 {
+		return nil
+	}
+	workload := &unstructured.Unstructured{}
+	if err := workload.UnmarshalJSON(work.Spec.Workload.Manifests[0].Raw); err != nil {
+		return nil
+	}
+	if workload.GroupVersionKind() != c.gvk {
+		return nil
+	}
+	key := client.ObjectKeyFromObject(workload)
+	if !isOwnedWork(work, key, "") {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
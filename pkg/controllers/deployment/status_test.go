@@ -0,0 +1,79 @@
+package deployment
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+)
+
+func TestWorkReference(t *testing.T) {
+	applied := func(status metav1.ConditionStatus) []metav1.Condition {
+		return []metav1.Condition{{Type: workv1alpha1.WorkApplied, Status: status, Message: "message"}}
+	}
+	manifest := func(raw string) []workv1alpha1.ManifestStatus {
+		return []workv1alpha1.ManifestStatus{{
+			Status: &runtime.RawExtension{Raw: []byte(raw)},
+			Health: workv1alpha1.ResourceHealthy,
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		status        workv1alpha1.WorkStatus
+		wantApplied   metav1.ConditionStatus
+		wantMessage   string
+		wantAvailable *int32
+		wantRolledOut bool
+	}{
+		{
+			name:        "not applied yet",
+			wantApplied: metav1.ConditionUnknown,
+		},
+		{
+			name:        "failed to apply",
+			status:      workv1alpha1.WorkStatus{Conditions: applied(metav1.ConditionFalse)},
+			wantApplied: metav1.ConditionFalse,
+			wantMessage: "message",
+		},
+		{
+			name:          "applied without replicas",
+			status:        workv1alpha1.WorkStatus{Conditions: applied(metav1.ConditionTrue), ManifestStatuses: manifest(`{}`)},
+			wantApplied:   metav1.ConditionTrue,
+			wantRolledOut: true,
+		},
+		{
+			name: "rolling out",
+			status: workv1alpha1.WorkStatus{Conditions: applied(metav1.ConditionTrue),
+				ManifestStatuses: manifest(`{"replicas":3,"updatedReplicas":3,"readyReplicas":1}`)},
+			wantApplied:   metav1.ConditionTrue,
+			wantAvailable: new(int32),
+		},
+		{
+			name: "rolled out",
+			status: workv1alpha1.WorkStatus{Conditions: applied(metav1.ConditionTrue),
+				ManifestStatuses: manifest(`{"replicas":3,"updatedReplicas":3,"readyReplicas":3,"availableReplicas":3}`)},
+			wantApplied:   metav1.ConditionTrue,
+			wantAvailable: func() *int32 { i := int32(3); return &i }(),
+			wantRolledOut: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			work := &workv1alpha1.Work{ObjectMeta: metav1.ObjectMeta{Namespace: "karmada-es-member1", Name: "nginx"}, Status: tt.status}
+			ref := workReference("member1", work)
+			if ref.Applied != tt.wantApplied || ref.Message != tt.wantMessage {
+				t.Errorf("applied = %q %q, want %q %q", ref.Applied, ref.Message, tt.wantApplied, tt.wantMessage)
+			}
+			if (ref.AvailableReplicas == nil) != (tt.wantAvailable == nil) ||
+				(ref.AvailableReplicas != nil && *ref.AvailableReplicas != *tt.wantAvailable) {
+				t.Errorf("availableReplicas = %v, want %v", ref.AvailableReplicas, tt.wantAvailable)
+			}
+			if got := rolledOut(&ref); got != tt.wantRolledOut {
+				t.Errorf("rolledOut() = %v, want %v", got, tt.wantRolledOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workv1alpha1 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
+)
+
+// newWork builds the Work which wraps the resource, it follows helper.CreateOrUpdateWork.
+func newWork(workMeta metav1.ObjectMeta, resource *unstructured.Unstructured) (*workv1alpha1.Work, error) {
+	workload := resource.DeepCopy()
+	// the finalizers guard the object in the karmada control plane, nothing would release them in the member cluster.
+	workload.SetFinalizers(nil)
+	karmadautil.MergeAnnotation(workload, workv1alpha2.ResourceTemplateUIDAnnotation, string(workload.GetUID()))
+	karmadautil.RecordManagedAnnotations(workload)
+	karmadautil.RecordManagedLabels(workload)
+	workloadJSON, err := workload.MarshalJSON()
+	if err != nil {
+		klog.Errorf("Failed to marshal workload(%s/%s), Error: %v", workload.GetNamespace(), workload.GetName(), err)
+		return nil, err
+	}
+
+	return &workv1alpha1.Work{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: workv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Work",
+		},
+		ObjectMeta: workMeta,
+		Spec: workv1alpha1.WorkSpec{
+			Workload: workv1alpha1.WorkloadTemplate{
+				Manifests: []workv1alpha1.Manifest{
+					{
+						RawExtension: runtime.RawExtension{
+							Raw: workloadJSON,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// createOrUpdateWork applies the Work of the resource for the cluster. It follows
+// helper.CreateOrUpdateWork but returns the applied Work with its status and
+// the operation that was performed, and refuses to overwrite a Work which was
+// not written for the resource.
+func (c *Controller) createOrUpdateWork(ctx context.Context, cluster string, workMeta metav1.ObjectMeta,
+	resource *unstructured.Unstructured) (_ *workv1alpha1.Work, _ controllerutil.OperationResult, err error) {
+	ctx, span := tracing.Start(ctx, "deployment.CreateOrUpdateWork", tracing.AttributeCluster.String(cluster),
+		tracing.AttributeNamespace.String(workMeta.Namespace), tracing.AttributeName.String(workMeta.Name))
+	defer func() { tracing.End(span, err) }()
+
+	work, err := newWork(workMeta, resource)
+	if err != nil {
+		return nil, controllerutil.OperationResultNone, err
+	}
+
+	var applied *workv1alpha1.Work
+	var operationResult controllerutil.OperationResult
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		var existing client.Object
+		var existingWork *workv1alpha1.Work
+		current := &workv1alpha1.Work{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(work), current); err == nil {
+			switch {
+			case isOwnedWork(current, client.ObjectKeyFromObject(resource), resource.GetUID()):
+			case isBindingWork(current, c.bindingKey(resource)):
+				// the Work was derived from the PropagationPolicy of the object, it is taken over.
+				if err := c.releaseBindingWork(ctx, current); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("work %s/%s exists and was not written for %s/%s: %w", work.Namespace, work.Name, resource.GetNamespace(), resource.GetName(), errNotOwned)
+			}
+			existing, existingWork = current, current
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		applied = work.DeepCopy()
+		setRevisionAnnotations(applied, existingWork, time.Now())
+		operationResult, err = c.apply(ctx, applied, existing)
+		return err
+	})
+	if err != nil {
+		klog.Errorf("Failed to create/update work %s/%s. Error: %v", work.GetNamespace(), work.GetName(), err)
+		return nil, controllerutil.OperationResultNone, err
+	}
+
+	if operationResult == controllerutil.OperationResultCreated {
+		klog.V(2).Infof("Create work %s/%s successfully.", work.GetNamespace(), work.GetName())
+	} else if operationResult == controllerutil.OperationResultUpdated {
+		klog.V(2).Infof("Update work %s/%s successfully.", work.GetNamespace(), work.GetName())
+	} else {
+		klog.V(2).Infof("Work %s/%s is up to date.", work.GetNamespace(), work.GetName())
+	}
+
+	return applied, operationResult, nil
+}
+
+// workNames returns the names of the Works the controller may have written for the object.
+func (c *Controller) workNames(key types.NamespacedName) sets.String {
+	workNames := sets.NewString(names.GenerateWorkName(c.gvk.Kind, key.Name, key.Namespace))
+	if c.gvk == deploymentGVK {
+		workNames.Insert(legacyWorkName(key))
+	}
+	return workNames
+}
+
+// legacyWorkName returns the name of the Work of a deployment written before the
+// controller handled other kinds, the kind was missing from the name.
+func legacyWorkName(key types.NamespacedName) string {
+	return names.GenerateWorkName("", key.Name, key.Namespace)
+}
+
+// removeLegacyWork deletes the Work of the deployment written under its legacy
+// name, once the Work with the current name is written.
+func (c *Controller) removeLegacyWork(ctx context.Context, workNamespace string, obj *unstructured.Unstructured) error {
+	if c.gvk != deploymentGVK {
+		return nil
+	}
+
+	work := &workv1alpha1.Work{}
+	key := types.NamespacedName{Namespace: workNamespace, Name: legacyWorkName(client.ObjectKeyFromObject(obj))}
+	if err := c.Client.Get(ctx, key, work); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !isOwnedWork(work, client.ObjectKeyFromObject(obj), obj.GetUID()) {
+		return nil
+	}
+	if err := c.Client.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete legacy work %s. Error: %v", key, err)
+		return err
+	}
+	klog.Infof("Delete legacy work %s of deployment %s/%s successful.", key, obj.GetNamespace(), obj.GetName())
+	return nil
+}
@@ -0,0 +1,36 @@
+package deployment
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewWork(t *testing.T) {
+	resource := &unstructured.Unstructured{}
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetNamespace("default")
+	resource.SetName("nginx")
+	resource.SetFinalizers([]string{finalizer})
+
+	work, err := newWork(metav1.ObjectMeta{Namespace: "karmada-es-member1", Name: "nginx"}, resource)
+	if err != nil {
+		t.Fatalf("newWork() error = %v", err)
+	}
+	manifests, err := decodeManifests(work)
+	if err != nil {
+		t.Fatalf("decodeManifests() error = %v", err)
+	}
+	if len(manifests) != 1 {
+		t.Fatalf("got %d manifests, want 1", len(manifests))
+	}
+	workload := &unstructured.Unstructured{Object: manifests[0]}
+	if finalizers := workload.GetFinalizers(); len(finalizers) != 0 {
+		t.Errorf("workload finalizers = %v, want none", finalizers)
+	}
+	if finalizers := resource.GetFinalizers(); len(finalizers) != 1 {
+		t.Errorf("resource finalizers = %v, want them untouched", finalizers)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
+	componentbaseconfig "k8s.io/component-base/config"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +18,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sync"
 
+	configv1alpha1 "github.com/prodanlabs/karmada-examples/pkg/apis/config/v1alpha1"
+	"github.com/prodanlabs/karmada-examples/pkg/features"
 	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
@@ -31,12 +36,34 @@ type Controller struct {
 	Clientset     *kubernetes.Clientset
 	karmadaClient karmadaclientset.Interface
 	mu            *sync.Mutex
+	config        configv1alpha1.DNSControllerConfiguration
+	// synced is the last DNSRecordsSynced message of every global service, guarded by mu.
+	synced map[types.NamespacedName]string
+	// dryRun computes the Corefile and logs the diff without writing anything.
+	dryRun bool
 }
 
+var _ manager.LeaderElectionRunnable = &Controller{}
+
 // Reconcile  The function does not differentiate between create, update or deletion events.
 // Instead it simply reads the state of the cluster at the time it is called.
 func (c *Controller) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
-	if err := c.deleteConfig(request.Name, request.Namespace); err != nil {
+	if features.FeatureGate.Enabled(features.EventDrivenDNS) {
+		service := &corev1.Service{}
+		err := c.Client.Get(ctx, request.NamespacedName, service)
+		if err == nil && service.DeletionTimestamp.IsZero() {
+			if err := c.addOrUpdateConfig(ctx); err != nil {
+				klog.Errorf("Failed to sync DNS resolution of service %s. error: %v", request.NamespacedName, err)
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, nil
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{Requeue: true}, err
+		}
+	}
+
+	if err := c.deleteConfig(ctx, request.Name, request.Namespace); err != nil {
 		klog.Errorf("Failed to remove obsolete DNS resolution. error: %v", err)
 		return reconcile.Result{Requeue: true}, nil
 	}
@@ -49,10 +76,10 @@ func (c *Controller) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 func (c *Controller) SetupWithManager(mgr manager.Manager) error {
 	predicate := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return false
+			return features.FeatureGate.Enabled(features.EventDrivenDNS) && c.isGlobalService(e.Object)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return false
+			return features.FeatureGate.Enabled(features.EventDrivenDNS) && c.isGlobalService(e.ObjectNew)
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			return true
@@ -65,6 +92,12 @@ func (c *Controller) SetupWithManager(mgr manager.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).For(&corev1.Service{}).WithEventFilter(predicate).Complete(c)
 }
 
+// isGlobalService checks whether the service is annotated to be resolved across clusters.
+func (c *Controller) isGlobalService(obj client.Object) bool {
+	v, ok := obj.GetAnnotations()[c.config.AnnotationPrefix+"/global"]
+	return ok && v == "true"
+}
+
 // AddToManager create controller and register to controller manager
 func (c *Controller) AddToManager(mgr manager.Manager) error {
 	// Setup Scheme for k8s appv1 resources
@@ -72,21 +105,34 @@ func (c *Controller) AddToManager(mgr manager.Manager) error {
 		return err
 	}
 
-	return c.SetupWithManager(mgr)
+	if err := c.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	return mgr.Add(c)
 }
 
-// NewController returns a new Controller
-func NewController(mgr manager.Manager) *Controller {
-	c, err := util.NewClientSet(mgr.GetConfig())
+// NewController returns a new Controller, in dry-run mode it writes nothing, events included.
+func NewController(mgr manager.Manager, config configv1alpha1.DNSControllerConfiguration,
+	clientConnection componentbaseconfig.ClientConnectionConfiguration, dryRun bool) *Controller {
+	c, err := util.NewClientSet(mgr.GetConfig(), clientConnection)
 	if err != nil {
 		klog.Fatal(err)
 	}
 
+	var recorder record.EventRecorder = &record.FakeRecorder{}
+	if !dryRun {
+		recorder = mgr.GetEventRecorderFor(ControllerName)
+	}
+
 	return &Controller{
 		Client:        mgr.GetClient(),
-		recorder:      mgr.GetEventRecorderFor(ControllerName),
+		recorder:      recorder,
 		karmadaClient: karmadaclientset.NewForConfigOrDie(mgr.GetConfig()),
 		Clientset:     c,
 		mu:            new(sync.Mutex),
+		config:        config,
+		synced:        map[types.NamespacedName]string{},
+		dryRun:        dryRun,
 	}
 }
@@ -7,11 +7,18 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
+	"github.com/prodanlabs/karmada-examples/pkg/events"
+	"github.com/prodanlabs/karmada-examples/pkg/metrics"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
 	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
@@ -22,27 +29,46 @@ type domainName struct {
 	hostname string
 }
 
-func (c *Controller) record(namespace, serviceName, labelSelector string, dn *[]domainName) error {
-	clusterList, err := c.karmadaClient.ClusterV1alpha1().Clusters().List(context.TODO(), metav1.ListOptions{})
+// syncedService is the number of records collected for a global service.
+type syncedService struct {
+	service  *corev1.Service
+	records  int
+	clusters int
+}
+
+// record appends the records of the service's pods to dn, it returns the number
+// of clusters which contributed records.
+func (c *Controller) record(ctx context.Context, namespace, serviceName, labelSelector string, dn *[]domainName) (int, error) {
+	clusterList, err := c.karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	var clusters int
+
 	for i := range clusterList.Items {
 		var podList corev1.PodList
+		start := time.Now()
+		spanCtx, span := tracing.Start(ctx, "dns.ListClusterPods", tracing.AttributeCluster.String(clusterList.Items[i].Name),
+			tracing.AttributeNamespace.String(namespace), tracing.AttributeName.String(serviceName))
 		data, err := c.karmadaClient.ClusterV1alpha1().
 			RESTClient().
 			Get().
 			RequestURI(fmt.Sprintf(uri, clusterList.Items[i].Name, namespace)).
 			Param("labelSelector", labelSelector).
-			//Timeout(60 * time.Second).
-			DoRaw(context.TODO())
+			Timeout(c.config.ClusterRequestTimeout.Duration).
+			DoRaw(spanCtx)
+		tracing.End(span, err)
+		metrics.ObserveClusterProxyCall(clusterList.Items[i].Name, start, err)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		if err := json.Unmarshal(data, &podList); err != nil {
-			return err
+			return 0, err
+		}
+		if len(podList.Items) != 0 {
+			clusters++
 		}
 
 		for i := range podList.Items {
@@ -53,13 +79,13 @@ func (c *Controller) record(namespace, serviceName, labelSelector string, dn *[]
 		}
 	}
 
-	return nil
+	return clusters, nil
 }
 
-func (c *Controller) filter() ([]corev1.Service, error) {
+func (c *Controller) filter(ctx context.Context) ([]corev1.Service, error) {
 	var compliantService []corev1.Service
 
-	services, err := c.Clientset.CoreV1().Services("").List(context.TODO(), metav1.ListOptions{})
+	services, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +95,7 @@ func (c *Controller) filter() ([]corev1.Service, error) {
 			break
 		}
 
-		annotations := services.Items[i].GetAnnotations()
-		if v, ok := annotations["service.karmada.io/global"]; ok && v == "true" {
+		if c.isGlobalService(&services.Items[i]) {
 			compliantService = append(compliantService, services.Items[i])
 		}
 	}
@@ -78,26 +103,56 @@ func (c *Controller) filter() ([]corev1.Service, error) {
 	return compliantService, nil
 }
 
-func (c *Controller) aggregation() ([]domainName, error) {
+func (c *Controller) aggregation(ctx context.Context) (_ []domainName, _ []syncedService, err error) {
 	var dn []domainName
-
-	services, err := c.filter()
+	var synced []syncedService
+	defer metrics.ObserveAggregationDuration(time.Now())
+	ctx, span := tracing.Start(ctx, "dns.Aggregation")
+	defer func() {
+		span.SetAttributes(attribute.Int("dns.services", len(synced)), attribute.Int("dns.records", len(dn)))
+		tracing.End(span, err)
+	}()
+
+	services, err := c.filter(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	metrics.ResetServiceRecords()
 	for i := range services {
 		selector := services[i].Spec.Selector
-		if err := c.record(services[i].Namespace, services[i].Name, util.MapToString(selector), &dn); err != nil {
-			return nil, err
+		records := len(dn)
+		clusters, err := c.record(ctx, services[i].Namespace, services[i].Name, util.MapToString(selector), &dn)
+		if err != nil {
+			c.recorder.Eventf(&services[i], corev1.EventTypeWarning, events.EventReasonDNSRecordsSyncFailed,
+				"Failed to collect DNS records from member clusters: %v", err)
+			return nil, nil, err
 		}
+		metrics.SetServiceRecords(services[i].Namespace, services[i].Name, len(dn)-records)
+		synced = append(synced, syncedService{service: &services[i], records: len(dn) - records, clusters: clusters})
 	}
 
 	if len(dn) == 0 {
-		return nil, nil
+		return nil, synced, nil
 	}
 
-	return dn, nil
+	return dn, synced, nil
+}
+
+// recordSyncedEvents records an event on every global service whose records
+// changed since the last sync.
+func (c *Controller) recordSyncedEvents(synced []syncedService) {
+	current := make(map[types.NamespacedName]string, len(synced))
+	for _, s := range synced {
+		key := types.NamespacedName{Namespace: s.service.Namespace, Name: s.service.Name}
+		message := fmt.Sprintf("%d records across %d clusters", s.records, s.clusters)
+		current[key] = message
+		if c.synced[key] == message {
+			continue
+		}
+		c.recorder.Event(s.service, corev1.EventTypeNormal, events.EventReasonDNSRecordsSynced, message)
+	}
+	c.synced = current
 }
 
 // lockState Get the state of the lock
@@ -110,7 +165,7 @@ func (c *Controller) lockState() error {
 	return nil
 }
 
-func (c *Controller) addOrUpdateConfig() error {
+func (c *Controller) addOrUpdateConfig(ctx context.Context) error {
 	if err := c.lockState(); err != nil {
 		return err
 	}
@@ -118,12 +173,12 @@ func (c *Controller) addOrUpdateConfig() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	dn, err := c.aggregation()
+	dn, synced, err := c.aggregation(ctx)
 	if err != nil {
 		return err
 	}
 
-	configMap, err := c.Clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(context.TODO(), "coredns", metav1.GetOptions{})
+	configMap, err := c.Clientset.CoreV1().ConfigMaps(c.config.ConfigMapNamespace).Get(ctx, c.config.ConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -136,21 +191,29 @@ func (c *Controller) addOrUpdateConfig() error {
 
 	if len(updateCorefile) == 0 {
 		klog.V(6).Info("the Corefile config of ConfigMaps is empty")
+		c.recordSyncedEvents(synced)
 		return nil
 	}
 
 	// update CoreDNS config
+	if c.dryRun {
+		c.dryRunCorefile(configMap, strings.ReplaceAll(string(updateCorefile), "\t", "    "))
+		return nil
+	}
 	configMap.Data["Corefile"] = strings.ReplaceAll(string(updateCorefile), "\t", "    ")
 	klog.V(6).Infof("The new configuration of A after the update:\n", configMap.Data["Corefile"])
-	if _, err = c.Clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+	_, err = c.Clientset.CoreV1().ConfigMaps(c.config.ConfigMapNamespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	metrics.RecordCorefileRewrite(err)
+	if err != nil {
 		return err
 	}
 
+	c.recordSyncedEvents(synced)
 	klog.Info("Corefile update complete.")
 	return nil
 }
 
-func (c *Controller) deleteConfig(serviceName, namespace string) error {
+func (c *Controller) deleteConfig(ctx context.Context, serviceName, namespace string) error {
 	if err := c.lockState(); err != nil {
 		return err
 	}
@@ -158,7 +221,7 @@ func (c *Controller) deleteConfig(serviceName, namespace string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	configMap, err := c.Clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(context.TODO(), "coredns", metav1.GetOptions{})
+	configMap, err := c.Clientset.CoreV1().ConfigMaps(c.config.ConfigMapNamespace).Get(ctx, c.config.ConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -167,21 +230,48 @@ func (c *Controller) deleteConfig(serviceName, namespace string) error {
 	d := corefile.Delete(fmt.Sprintf("%s.%s", serviceName, namespace))
 
 	// update CoreDNS config
+	if c.dryRun {
+		c.dryRunCorefile(configMap, strings.ReplaceAll(string(d), "\t", "    "))
+		return nil
+	}
 	configMap.Data["Corefile"] = strings.ReplaceAll(string(d), "\t", "    ")
 	klog.V(6).Infof("Corefile new configuration after deletion:\n", configMap.Data["Corefile"])
-	if _, err = c.Clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+	_, err = c.Clientset.CoreV1().ConfigMaps(c.config.ConfigMapNamespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	metrics.RecordCorefileRewrite(err)
+	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Controller) Worker(interval time.Duration) {
-	for {
-		if err := c.addOrUpdateConfig(); err != nil {
+// Start implements manager.Runnable, it periodically aggregates the pods of
+// global services and writes them into the Corefile until ctx is done.
+func (c *Controller) Start(ctx context.Context) error {
+	klog.Infof("Starting DNS sync loop, interval: %v", c.config.SyncInterval.Duration)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.addOrUpdateConfig(ctx); err != nil {
 			klog.Error(err)
+			return
 		}
+		metrics.RecordDNSSync()
+	}, c.config.SyncInterval.Duration)
+	klog.Info("Stopped DNS sync loop")
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, only the
+// leader is allowed to rewrite the Corefile.
+func (c *Controller) NeedLeaderElection() bool {
+	return true
+}
 
-		time.Sleep(interval)
+// dryRunCorefile logs the difference between the current Corefile and the desired one.
+func (c *Controller) dryRunCorefile(configMap *corev1.ConfigMap, corefile string) {
+	diff := cmp.Diff(configMap.Data["Corefile"], corefile)
+	if diff == "" {
+		klog.Infof("[dry-run] Corefile of ConfigMap %s/%s is up to date", configMap.Namespace, configMap.Name)
+		return
 	}
+	klog.Infof("[dry-run] would update Corefile of ConfigMap %s/%s (-current +desired):\n%s", configMap.Namespace, configMap.Name, diff)
 }
@@ -0,0 +1,55 @@
+package events
+
+// Define events for deployment controller.
+const (
+	// EventReasonWorkCreated indicates that a Work was created for a member cluster.
+	EventReasonWorkCreated = "WorkCreated"
+	// EventReasonWorkUpdated indicates that a Work was updated for a member cluster.
+	EventReasonWorkUpdated = "WorkUpdated"
+	// EventReasonWorkSyncFailed indicates that creating or updating a Work failed.
+	EventReasonWorkSyncFailed = "WorkSyncFailed"
+	// EventReasonWorkDeleted indicates that a Work was deleted from a member cluster.
+	EventReasonWorkDeleted = "WorkDeleted"
+	// EventReasonWorkDeleteFailed indicates that deleting a Work failed.
+	EventReasonWorkDeleteFailed = "WorkDeleteFailed"
+	// EventReasonWorkConflict indicates that a Work with the same name exists and was not written for the resource.
+	EventReasonWorkConflict = "WorkConflict"
+	// EventReasonWorkPruned indicates that a Work was deleted from a member cluster which is no longer a target.
+	EventReasonWorkPruned = "WorkPruned"
+	// EventReasonWorkEvicted indicates that a Work was deleted from a member cluster which stayed NotReady past the grace period or is tainted NoExecute.
+	EventReasonWorkEvicted = "WorkEvicted"
+	// EventReasonInvalidClusterTolerations indicates that the cluster tolerations annotation cannot be decoded.
+	EventReasonInvalidClusterTolerations = "InvalidClusterTolerations"
+	// EventReasonPropagationPolicyApplied indicates that a PropagationPolicy was created or updated.
+	EventReasonPropagationPolicyApplied = "PropagationPolicyApplied"
+	// EventReasonPropagationPolicyApplyFailed indicates that creating or updating a PropagationPolicy failed.
+	EventReasonPropagationPolicyApplyFailed = "PropagationPolicyApplyFailed"
+	// EventReasonPropagationPolicyConflict indicates that a PropagationPolicy with the same name exists and is not controlled by the resource.
+	EventReasonPropagationPolicyConflict = "PropagationPolicyConflict"
+	// EventReasonPropagationPolicyRemoved indicates that a PropagationPolicy was deleted as its resource has no target cluster left.
+	EventReasonPropagationPolicyRemoved = "PropagationPolicyRemoved"
+	// EventReasonModeSwitched indicates that the artifacts of the previous distribution mode were removed.
+	EventReasonModeSwitched = "ModeSwitched"
+	// EventReasonRolloutComplete indicates that every wave of a staged rollout rolled the revision out.
+	EventReasonRolloutComplete = "RolloutComplete"
+	// EventReasonRolloutHalted indicates that a wave of a staged rollout failed and the rollout stopped.
+	EventReasonRolloutHalted = "RolloutHalted"
+	// EventReasonRolloutRolledBack indicates that a wave of a staged rollout failed and the updated clusters were reverted.
+	EventReasonRolloutRolledBack = "RolloutRolledBack"
+	// EventReasonOverrideFailed indicates that the overrides of a member cluster cannot be applied to a Work.
+	EventReasonOverrideFailed = "OverrideFailed"
+	// EventReasonDependencyNotFound indicates that a dependency the pod template references does not exist.
+	EventReasonDependencyNotFound = "DependencyNotFound"
+	// EventReasonSkippedNoTargetClusters indicates that none of the requested member clusters exist.
+	EventReasonSkippedNoTargetClusters = "SkippedNoTargetClusters"
+	// EventReasonInvalidClusterSelector indicates that the cluster selector annotation cannot be parsed.
+	EventReasonInvalidClusterSelector = "InvalidClusterSelector"
+)
+
+// Define events for dns controller.
+const (
+	// EventReasonDNSRecordsSynced indicates that the records of a global service were written to the Corefile.
+	EventReasonDNSRecordsSynced = "DNSRecordsSynced"
+	// EventReasonDNSRecordsSyncFailed indicates that collecting the records of a global service failed.
+	EventReasonDNSRecordsSyncFailed = "DNSRecordsSyncFailed"
+)
@@ -0,0 +1,39 @@
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// EventDrivenDNS syncs the Corefile on global Service create and update events,
+	// in addition to the periodic sync.
+	EventDrivenDNS featuregate.Feature = "EventDrivenDNS"
+
+	// ClusterHealthAwarePlacement skips member clusters which are not Ready, or
+	// carry NoSchedule or NoExecute taints the object does not tolerate, when
+	// selecting its target clusters. The Works of a cluster tainted NoExecute, or
+	// NotReady, past the eviction grace period or the toleration seconds are evicted.
+	ClusterHealthAwarePlacement featuregate.Feature = "ClusterHealthAwarePlacement"
+
+	// PullModeClusterAccess accesses Pull mode member clusters through the karmada
+	// cluster proxy, which requires apiserver-network-proxy (ANP) to be deployed.
+	// https://github.com/karmada-io/karmada/blob/master/docs/userguide/aggregated-api-endpoint.md
+	PullModeClusterAccess featuregate.Feature = "PullModeClusterAccess"
+)
+
+var (
+	// FeatureGate is a shared global FeatureGate.
+	FeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+	// DefaultFeatureGates is the default feature gates.
+	DefaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+		EventDrivenDNS:              {Default: false, PreRelease: featuregate.Alpha},
+		ClusterHealthAwarePlacement: {Default: false, PreRelease: featuregate.Alpha},
+		PullModeClusterAccess:       {Default: false, PreRelease: featuregate.Alpha},
+	}
+)
+
+func init() {
+	runtime.Must(FeatureGate.Add(DefaultFeatureGates))
+}
@@ -64,6 +64,9 @@ func (o *LogsPullOptions) Validate() error {
 	if o.GlobalOptions.Kubeconfig == "" {
 		return fmt.Errorf("absolute path to the kubeconfig file")
 	}
+	if err := util.ValidateClientConnection(o.GlobalOptions.ClientConnection); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -123,7 +126,7 @@ func (o *LogsPullOptions) Run() error {
 	}
 	cfg.Dial = dialerTunnel.DialContext
 
-	clientSet, err := util.NewClientSet(cfg)
+	clientSet, err := util.NewClientSet(cfg, o.GlobalOptions.ClientConnection)
 	if err != nil {
 		return err
 	}
@@ -1,17 +1,27 @@
 package options
 
-import "github.com/spf13/pflag"
+import (
+	"github.com/spf13/pflag"
+	componentbaseconfig "k8s.io/component-base/config"
+
+	"github.com/prodanlabs/karmada-examples/pkg/util"
+)
 
 type GlobalOptions struct {
 	Namespace  string
 	Kubeconfig string
+	// ClientConnection specifies the QPS, burst and content type of the clients talking to the karmada apiserver.
+	ClientConnection componentbaseconfig.ClientConnectionConfiguration
 }
 
 func NewGlobalOptions() *GlobalOptions {
-	return &GlobalOptions{}
+	return &GlobalOptions{
+		ClientConnection: util.DefaultClientConnection(),
+	}
 }
 
 func (o *GlobalOptions) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&o.Namespace, "namespace", "n", "default", "kubernetes name")
 	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "--kubeconfig absolute path to the kubeconfig file")
+	util.AddClientConnectionFlags(flags, &o.ClientConnection)
 }
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/featuregate"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "karmada_custom"
+
+	// OperationCreated marks an object which was created.
+	OperationCreated = "created"
+	// OperationUpdated marks an object which was updated.
+	OperationUpdated = "updated"
+	// OperationDeleted marks an object which was deleted.
+	OperationDeleted = "deleted"
+)
+
+var (
+	workOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "deployment_controller",
+		Name:      "works_total",
+		Help:      "Number of Works created, updated and deleted by the deployment controller, labeled by member cluster and operation.",
+	}, []string{"cluster", "operation"})
+
+	propagationPolicyOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "deployment_controller",
+		Name:      "propagation_policies_total",
+		Help:      "Number of PropagationPolicies generated by the deployment controller, labeled by operation.",
+	}, []string{"operation"})
+
+	corefileRewrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "corefile_rewrites_total",
+		Help:      "Number of times the Corefile was rewritten, labeled by result.",
+	}, []string{"result"})
+
+	lastCorefileSync = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "last_sync_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful DNS sync, whether or not the Corefile changed.",
+	})
+
+	serviceRecords = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "service_records",
+		Help:      "Number of DNS records managed per global service.",
+	}, []string{"namespace", "service"})
+
+	clusterProxyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "cluster_proxy_duration_seconds",
+		Help:      "Duration in seconds of the calls to member clusters through the karmada cluster proxy.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"cluster"})
+
+	clusterProxyErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "cluster_proxy_errors_total",
+		Help:      "Number of failed calls to member clusters through the karmada cluster proxy.",
+	}, []string{"cluster"})
+
+	featureEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "feature_enabled",
+		Help:      "Whether a feature gate is enabled (1) or not (0), labeled by feature name and stage.",
+	}, []string{"name", "stage"})
+
+	aggregationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "dns_controller",
+		Name:      "aggregation_duration_seconds",
+		Help:      "Duration in seconds of aggregating the DNS records of all global services.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		workOperations,
+		propagationPolicyOperations,
+		corefileRewrites,
+		lastCorefileSync,
+		serviceRecords,
+		clusterProxyDuration,
+		clusterProxyErrors,
+		aggregationDuration,
+		featureEnabled,
+	)
+}
+
+// RecordFeatureGates records the state of every known feature gate.
+func RecordFeatureGates(gate featuregate.MutableFeatureGate) {
+	for feature, spec := range gate.GetAll() {
+		// skip the AllAlpha and AllBeta pseudo features.
+		if feature == "AllAlpha" || feature == "AllBeta" {
+			continue
+		}
+		var enabled float64
+		if gate.Enabled(feature) {
+			enabled = 1
+		}
+		stage := string(spec.PreRelease)
+		if spec.PreRelease == featuregate.GA {
+			stage = "GA"
+		}
+		featureEnabled.WithLabelValues(string(feature), stage).Set(enabled)
+	}
+}
+
+// RecordWorkOperation records a Work operation in the given member cluster.
+func RecordWorkOperation(cluster, operation string) {
+	workOperations.WithLabelValues(cluster, operation).Inc()
+}
+
+// RecordPropagationPolicyOperation records a PropagationPolicy operation.
+func RecordPropagationPolicyOperation(operation string) {
+	propagationPolicyOperations.WithLabelValues(operation).Inc()
+}
+
+// RecordCorefileRewrite records the result of a Corefile rewrite.
+func RecordCorefileRewrite(err error) {
+	if err != nil {
+		corefileRewrites.WithLabelValues("error").Inc()
+		return
+	}
+	corefileRewrites.WithLabelValues("success").Inc()
+}
+
+// RecordDNSSync records the time of a successful DNS sync.
+func RecordDNSSync() {
+	lastCorefileSync.SetToCurrentTime()
+}
+
+// ResetServiceRecords drops the record counts of all services, so that deleted
+// services do not linger.
+func ResetServiceRecords() {
+	serviceRecords.Reset()
+}
+
+// SetServiceRecords sets the number of DNS records managed for a service.
+func SetServiceRecords(namespace, service string, records int) {
+	serviceRecords.WithLabelValues(namespace, service).Set(float64(records))
+}
+
+// ObserveClusterProxyCall records the duration and result of a call through the cluster proxy.
+func ObserveClusterProxyCall(cluster string, start time.Time, err error) {
+	clusterProxyDuration.WithLabelValues(cluster).Observe(time.Since(start).Seconds())
+	if err != nil {
+		clusterProxyErrors.WithLabelValues(cluster).Inc()
+	}
+}
+
+// ObserveAggregationDuration records the duration of the DNS aggregation.
+func ObserveAggregationDuration(start time.Time) {
+	aggregationDuration.Observe(time.Since(start).Seconds())
+}
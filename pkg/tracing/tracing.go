@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+	componentbasetracing "k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	instrumentationName = "github.com/prodanlabs/karmada-examples"
+
+	// DefaultSamplingRatePerMillion samples every span once an endpoint is set,
+	// lower it on busy control planes.
+	DefaultSamplingRatePerMillion = int32(1000000)
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Attribute keys shared by the spans of the controllers and the webhook.
+const (
+	AttributeCluster   = attribute.Key("karmada.cluster")
+	AttributeNamespace = attribute.Key("k8s.namespace.name")
+	AttributeName      = attribute.Key("k8s.object.name")
+	AttributeKind      = attribute.Key("k8s.object.kind")
+)
+
+// Options holds the flags of the OTLP trace exporter.
+type Options struct {
+	// Endpoint is the OTLP gRPC endpoint the spans are exported to, tracing is disabled when empty.
+	Endpoint string
+	// SamplingRatePerMillion is the number of samples to collect per million spans.
+	SamplingRatePerMillion int32
+}
+
+// AddFlags adds the tracing flags to the specified FlagSet.
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Endpoint, "tracing-endpoint", o.Endpoint, "The OTLP gRPC endpoint (e.g. localhost:4317) spans are exported to. Tracing is disabled when empty.")
+	flags.Int32Var(&o.SamplingRatePerMillion, "tracing-sampling-rate-per-million", o.SamplingRatePerMillion, "The number of spans to sample per million, 1000000 samples every reconcile.")
+}
+
+// Configuration returns the tracing configuration, nil when tracing is disabled.
+func (o *Options) Configuration() *tracingapi.TracingConfiguration {
+	if o.Endpoint == "" {
+		return nil
+	}
+	endpoint, rate := o.Endpoint, o.SamplingRatePerMillion
+	return &tracingapi.TracingConfiguration{Endpoint: &endpoint, SamplingRatePerMillion: &rate}
+}
+
+// ApplyConfiguration copies the tracing configuration into Options.
+func (o *Options) ApplyConfiguration(cfg *tracingapi.TracingConfiguration) {
+	if cfg == nil {
+		return
+	}
+	if cfg.Endpoint != nil {
+		o.Endpoint = *cfg.Endpoint
+	}
+	if cfg.SamplingRatePerMillion != nil {
+		o.SamplingRatePerMillion = *cfg.SamplingRatePerMillion
+	}
+}
+
+// Setup installs the global TracerProvider which exports to the configured
+// endpoint, and wraps config so that the requests sent to the karmada apiserver
+// become child spans. The returned provider must be shut down to flush the spans.
+func Setup(ctx context.Context, serviceName string, cfg *tracingapi.TracingConfiguration, config *rest.Config) (componentbasetracing.TracerProvider, error) {
+	tp, err := componentbasetracing.NewProvider(ctx, cfg, nil, []resource.Option{
+		resource.WithAttributes(semconv.ServiceNameKey.String(serviceName)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(componentbasetracing.Propagators())
+	if cfg != nil {
+		config.Wrap(componentbasetracing.WrapperFor(tp))
+	}
+	return tp, nil
+}
+
+// Shutdown flushes the pending spans and stops the exporter of tp.
+func Shutdown(tp componentbasetracing.TracerProvider) {
+	// the context of the component is already done here, give the exporter its own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		klog.Errorf("Failed to shut down the tracer provider: %v", err)
+	}
+}
+
+// Start starts a span from the global TracerProvider, it is a noop until Setup is called.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records err on the span if any, then ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
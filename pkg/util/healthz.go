@@ -0,0 +1,80 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// cacheSyncTimeout bounds how long a readiness probe waits for the informer caches.
+const cacheSyncTimeout = 2 * time.Second
+
+// APIResourcesChecker returns a healthz.Checker which fails unless every group version
+// is served by the apiserver and exposes the given resources, i.e. the CRDs are installed.
+func APIResourcesChecker(client discovery.DiscoveryInterface, resources map[schema.GroupVersion][]string) healthz.Checker {
+	return func(_ *http.Request) error {
+		for gv, names := range resources {
+			list, err := client.ServerResourcesForGroupVersion(gv.String())
+			if err != nil {
+				return fmt.Errorf("failed to discover group version %q: %v", gv, err)
+			}
+
+			served := sets.NewString()
+			for _, r := range list.APIResources {
+				served.Insert(r.Name)
+			}
+			if missing := sets.NewString(names...).Difference(served); missing.Len() != 0 {
+				return fmt.Errorf("resources %v of group version %q are not installed", missing.List(), gv)
+			}
+		}
+		return nil
+	}
+}
+
+// CacheSyncChecker returns a healthz.Checker which fails until the informer caches are synced.
+func CacheSyncChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+
+		if !c.WaitForCacheSync(ctx) {
+			return fmt.Errorf("informer caches are not synced")
+		}
+		return nil
+	}
+}
+
+// ServingCertChecker returns a healthz.Checker which fails if the serving certificate
+// can not be loaded or is not valid at the moment.
+func ServingCertChecker(certDir, certName, keyName string) healthz.Checker {
+	certFile, keyFile := filepath.Join(certDir, certName), filepath.Join(certDir, keyName)
+	return func(_ *http.Request) error {
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load X509 key pair %s and %s: %v", certFile, keyFile, err)
+		}
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate %s: %v", certFile, err)
+		}
+
+		now := time.Now()
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("certificate %s is not valid before %v", certFile, cert.NotBefore)
+		}
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate %s expired at %v", certFile, cert.NotAfter)
+		}
+		return nil
+	}
+}
@@ -1,24 +1,69 @@
 package util
 
 import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	componentbaseconfig "k8s.io/component-base/config"
 )
 
 const (
 	KubeQPS            = float32(5.000000)
 	KubeBurst          = 10
-	AcceptContentTypes = "application/json"
-	ContentType        = "application/json"
+	AcceptContentTypes = runtime.ContentTypeJSON
+	ContentType        = runtime.ContentTypeJSON
+	// ProtobufAcceptContentTypes prefers protobuf and falls back to JSON.
+	ProtobufAcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
 )
 
+// DefaultClientConnection returns the client connection settings used when nothing is configured.
+func DefaultClientConnection() componentbaseconfig.ClientConnectionConfiguration {
+	return componentbaseconfig.ClientConnectionConfiguration{
+		QPS:         KubeQPS,
+		Burst:       KubeBurst,
+		ContentType: ContentType,
+	}
+}
+
+// AddClientConnectionFlags adds the flags of the clients talking to the karmada apiserver.
+func AddClientConnectionFlags(flags *pflag.FlagSet, cc *componentbaseconfig.ClientConnectionConfiguration) {
+	flags.Float32Var(&cc.QPS, "kube-api-qps", cc.QPS, "QPS to use while talking with karmada-apiserver.")
+	flags.Int32Var(&cc.Burst, "kube-api-burst", cc.Burst, "Burst to use while talking with karmada-apiserver.")
+	flags.StringVar(&cc.ContentType, "kube-api-content-type", cc.ContentType,
+		fmt.Sprintf("Content type of requests sent to karmada-apiserver, %s or %s. Protobuf only applies to built-in types, karmada types always use JSON.", runtime.ContentTypeJSON, runtime.ContentTypeProtobuf))
+}
+
+// ValidateClientConnection checks the client connection settings.
+func ValidateClientConnection(cc componentbaseconfig.ClientConnectionConfiguration) error {
+	if cc.QPS < 0 {
+		return fmt.Errorf("kube-api-qps must be non-negative, got %v", cc.QPS)
+	}
+	if cc.Burst < 0 {
+		return fmt.Errorf("kube-api-burst must be non-negative, got %v", cc.Burst)
+	}
+	if cc.ContentType != "" && cc.ContentType != runtime.ContentTypeJSON && cc.ContentType != runtime.ContentTypeProtobuf {
+		return fmt.Errorf("kube-api-content-type must be %s or %s, got %q", runtime.ContentTypeJSON, runtime.ContentTypeProtobuf, cc.ContentType)
+	}
+	return nil
+}
+
 // SetupKubeConfig set parameter
-func SetupKubeConfig(config *rest.Config) {
-	config.QPS = KubeQPS
-	config.Burst = KubeBurst
+// When protobuf is configured the content type is left empty, so that
+// controller-runtime negotiates protobuf for built-in types only and the
+// karmada clients fall back to JSON.
+func SetupKubeConfig(config *rest.Config, cc componentbaseconfig.ClientConnectionConfiguration) {
+	config.QPS = cc.QPS
+	config.Burst = int(cc.Burst)
 	config.ContentType = ContentType
 	config.AcceptContentTypes = AcceptContentTypes
+	if cc.ContentType == runtime.ContentTypeProtobuf {
+		config.ContentType = ""
+		config.AcceptContentTypes = ""
+	}
 	config.UserAgent = rest.DefaultKubernetesUserAgent()
 }
 
@@ -32,8 +77,14 @@ func RestConfig(inCluster bool, kubeConfigPath string) (*rest.Config, error) {
 }
 
 // NewClientSet Kubernetes ClientSet
-func NewClientSet(c *rest.Config) (*kubernetes.Clientset, error) {
-	SetupKubeConfig(c)
+// The given config is copied, so the settings do not leak into other clients built from it.
+func NewClientSet(c *rest.Config, cc componentbaseconfig.ClientConnectionConfiguration) (*kubernetes.Clientset, error) {
+	config := rest.CopyConfig(c)
+	SetupKubeConfig(config, cc)
+	if cc.ContentType == runtime.ContentTypeProtobuf {
+		config.ContentType = runtime.ContentTypeProtobuf
+		config.AcceptContentTypes = ProtobufAcceptContentTypes
+	}
 
-	return kubernetes.NewForConfig(c)
+	return kubernetes.NewForConfig(config)
 }
@@ -4,16 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	componentbaseconfig "k8s.io/component-base/config"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/prodanlabs/karmada-examples/pkg/features"
+	"github.com/prodanlabs/karmada-examples/pkg/tracing"
 	"github.com/prodanlabs/karmada-examples/pkg/util"
 )
 
@@ -23,17 +30,20 @@ type ValidatingAdmission struct {
 	Clientset *kubernetes.Clientset
 	Config    *rest.Config
 	decoder   *admission.Decoder
+	// clusterRequestTimeout is the timeout of a single request sent to a member cluster.
+	clusterRequestTimeout time.Duration
 }
 
-func NewValidatingAdmission(mgr manager.Manager) *ValidatingAdmission {
-	clientset, err := util.NewClientSet(mgr.GetConfig())
+func NewValidatingAdmission(mgr manager.Manager, clientConnection componentbaseconfig.ClientConnectionConfiguration, clusterRequestTimeout time.Duration) *ValidatingAdmission {
+	clientset, err := util.NewClientSet(mgr.GetConfig(), clientConnection)
 	if err != nil {
 		klog.Fatal(err)
 	}
 	return &ValidatingAdmission{
-		Client:    mgr.GetClient(),
-		Clientset: clientset,
-		Config:    mgr.GetConfig(),
+		Client:                mgr.GetClient(),
+		Clientset:             clientset,
+		Config:                mgr.GetConfig(),
+		clusterRequestTimeout: clusterRequestTimeout,
 	}
 }
 
@@ -43,6 +53,10 @@ var _ admission.Handler = &ValidatingAdmission{}
 // Handle implements admission.Handler interface.
 // It yields a response to an AdmissionRequest.
 func (v *ValidatingAdmission) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, span := tracing.Start(ctx, "namespace.Handle", tracing.AttributeName.String(req.Name),
+		attribute.String("admission.operation", string(req.Operation)))
+	defer span.End()
+
 	klog.Infof("%s namespace %q", req.Operation, req.Name)
 	clusters, err := v.ClusterList(ctx)
 	if err != nil {
@@ -50,7 +64,9 @@ func (v *ValidatingAdmission) Handle(ctx context.Context, req admission.Request)
 	}
 
 	for _, c := range clusters {
-		if err := v.PodList(c, req.Name); err != nil {
+		if err := v.PodList(ctx, c, req.Name); err != nil {
+			span.SetAttributes(tracing.AttributeCluster.String(c))
+			span.SetStatus(codes.Error, err.Error())
 			return admission.Denied(err.Error())
 		}
 	}
@@ -66,9 +82,9 @@ func (v *ValidatingAdmission) ClusterList(ctx context.Context) ([]string, error)
 
 	var clusters []string
 	for _, c := range clusterList.Items {
-		// TODO Pull 模式可以部署 apiserver-network-proxy（ANP）来访问。这里跳过.
+		// Pull 模式可以部署 apiserver-network-proxy（ANP）来访问, 未开启 PullModeClusterAccess 时跳过.
 		// https://github.com/karmada-io/karmada/blob/master/docs/userguide/aggregated-api-endpoint.md
-		if c.Spec.SyncMode == clusterv1alpha1.Pull {
+		if c.Spec.SyncMode == clusterv1alpha1.Pull && !features.FeatureGate.Enabled(features.PullModeClusterAccess) {
 			continue
 		}
 		clusters = append(clusters, c.Name)
@@ -76,10 +92,17 @@ func (v *ValidatingAdmission) ClusterList(ctx context.Context) ([]string, error)
 	return clusters, nil
 }
 
-func (v *ValidatingAdmission) PodList(cluster, namespace string) error {
+func (v *ValidatingAdmission) PodList(ctx context.Context, cluster, namespace string) (err error) {
+	ctx, span := tracing.Start(ctx, "namespace.PodList",
+		tracing.AttributeCluster.String(cluster), tracing.AttributeNamespace.String(namespace))
+	defer func() { tracing.End(span, err) }()
+
 	uil := fmt.Sprintf("%s/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/api/v1/namespaces/%s/pods", v.Config.Host, cluster, namespace)
-	request := v.Clientset.RESTClient().Get().RequestURI(uil)
-	result := request.Do(context.Background())
+	// the member cluster answers through the proxy, always ask for JSON.
+	request := v.Clientset.RESTClient().Get().RequestURI(uil).
+		SetHeader("Accept", runtime.ContentTypeJSON).
+		Timeout(v.clusterRequestTimeout)
+	result := request.Do(ctx)
 	if err := result.Error(); err != nil {
 		return fmt.Errorf("result err: %v", err)
 	}